@@ -0,0 +1,174 @@
+package behance_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/behance"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = behance.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Behance"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://www.behance.net/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"ab",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    3,
+					Actual: 2,
+				},
+			},
+		}, {
+			"valid",
+			"Jane_Doe42",
+			noViolations,
+		}, {
+			"hyphen",
+			"jane-doe",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{4},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"toolong",
+			"aaaaaaaaaaaaaaaaaaaaa",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    20,
+					Actual: 21,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+		message  string // if not empty
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "profile",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/profile.http"),
+			status:   usrname.UnknownStatus,
+			message:  "profile page, going by unverified markup",
+		}, {
+			label:    "softnotfound",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/softnotfound.http"),
+			status:   usrname.UnknownStatus,
+			message:  "error page, going by unverified markup",
+		}, {
+			label:    "bothmarkers",
+			username: "janedoe",
+			client: mockclient.NewScript().On("", "", mockclient.Response{
+				StatusCode: http.StatusOK,
+				Body:       `<body class="error-page"><meta property="og:type" content="profile">`,
+			}),
+			status: usrname.UnknownStatus,
+		}, {
+			label:    "notfound",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/notfound.http"),
+			status:   usrname.Available,
+		}, {
+			label:    "challenge",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/challenge.http"),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+			if c.message != "" && res.Message != c.message {
+				t.Errorf("Check(%q), got message %q, want %q", c.username, res.Message, c.message)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -0,0 +1,293 @@
+package bluesky
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+// Resolver looks up DNS TXT records; *net.Resolver satisfies it.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// dnsTimeout bounds DNS lookups, as the client's timeout bounds requests.
+const dnsTimeout = 1000 * time.Millisecond
+
+// bluesky checks handles, which are domain names. Bare names designate
+// subdomains of pdsHost, whose handles only the PDS can resolve.
+type bluesky struct {
+	name            string
+	scheme          string
+	host            string
+	pdsHost         string
+	resolver        Resolver
+	illegalPrefixes []string
+	illegalSuffixes []string
+	illegalPattern  *regexp.Regexp
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+var blueskyImpl = bluesky{
+	name:            "Bluesky",
+	scheme:          "https",
+	host:            "bsky.app",
+	pdsHost:         "bsky.social",
+	resolver:        internal.Resolver{},
+	illegalPrefixes: []string{"-", "."},
+	illegalSuffixes: []string{
+		"-", ".",
+		// TLDs disallowed by the AT Protocol
+		".alt", ".arpa", ".example", ".internal", ".invalid", ".local",
+		".localhost", ".onion",
+	},
+	// empty labels, labels that start or end with a hyphen or that are
+	// longer than 63 characters, TLDs that start with a digit, and names on
+	// the PDS whose first label is shorter than 3 or longer than 18
+	// characters
+	illegalPattern: regexp.MustCompile(`\.\.|\.-|-\.|[^.]{64}|\.[0-9][^.]*$|` +
+		`(?i:^(?:[^.]{1,2}|[^.]{19,})(?:\.bsky\.social)?$)`),
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 1,
+	maxLength: 253,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"atproto", "decentralized", "microblogging"},
+		Homepage:  "https://bsky.app",
+		RulesURL:  "https://atproto.com/specs/handle",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(blueskyImpl.name, &blueskyImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &blueskyImpl
+}
+
+// NewWithResolver returns a Checker that looks DNS records up through r
+// instead of the system's resolver.
+func NewWithResolver(r Resolver) usrname.Checker {
+	c := blueskyImpl
+	c.resolver = r
+	return &c
+}
+
+// handle returns the full handle that username designates.
+func (s *bluesky) handle(username string) string {
+	h := strings.ToLower(strings.TrimPrefix(username, "@"))
+	if !strings.Contains(h, ".") {
+		h += "." + s.pdsHost
+	}
+	return h
+}
+
+func (s *bluesky) Name() string {
+	return s.name
+}
+
+func (s *bluesky) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *bluesky) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/profile/" + s.handle(username),
+	}
+	return u.String()
+}
+
+func (v *bluesky) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *bluesky) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *bluesky) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		IllegalSuffixes: append([]string(nil), v.illegalSuffixes...),
+		IllegalPattern:  v.illegalPattern.String(),
+	}
+}
+
+// Handles are case-insensitive, and bare names designate subdomains of the
+// PDS.
+func (s *bluesky) Canonicalize(username string) string {
+	return s.handle(username)
+}
+
+// Check also requires handles outside the PDS to be public DNS names (see
+// internal.IsPublicHost).
+//
+// See https://atproto.com/specs/handle
+func (v *bluesky) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckIllegalSuffixes(v.illegalSuffixes),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *bluesky) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(strings.TrimPrefix(username, "@")); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		handle := c.handle(username)
+		if strings.HasSuffix(handle, "."+c.pdsHost) {
+			return c.checkPDS(client, handle, r)
+		}
+		if !internal.IsPublicHost(handle) {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+		if did, err := c.lookupDNS(handle); err != nil {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("DNS lookup failed: %v", err)
+			return
+		} else if did {
+			r.Status = usrname.Unavailable
+			return
+		}
+		return c.checkHTTPS(client, handle, r)
+	}
+}
+
+// lookupDNS reports whether handle is bound to a DID through a TXT record.
+func (c *bluesky) lookupDNS(handle string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+	defer cancel()
+	records, err := c.resolver.LookupTXT(ctx, "_atproto."+handle)
+	if internal.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, rec := range records {
+		if strings.HasPrefix(rec, "did=did:") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkHTTPS looks for a DID at the well-known location of the domain.
+func (c *bluesky) checkHTTPS(client usrname.Client, handle string, r usrname.Result) usrname.Result {
+	u := url.URL{
+		Scheme: "https",
+		Host:   handle,
+		Path:   "/.well-known/atproto-did",
+	}
+	res, err := do(client, u)
+	if err != nil {
+		r.Status = usrname.UnknownStatus
+		if internal.IsTimeout(err) {
+			r.Message = fmt.Sprintf("%s timed out", handle)
+		} else {
+			r.Message = "Something went wrong"
+		}
+		return r
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		body, err := internal.ReadBody(res)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			r.Message = "unexpected response body"
+		} else if bytes.HasPrefix(bytes.TrimSpace(body), []byte("did:")) {
+			r.Status = usrname.Unavailable
+		} else {
+			r.Status = usrname.Available
+		}
+	case http.StatusNotFound:
+		r.Status = usrname.Available
+	default:
+		r.Status = usrname.UnknownStatus
+		r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+	}
+	return r
+}
+
+// checkPDS asks the PDS to resolve handle, which it fails to do (with status
+// code 400) if no account holds it.
+func (c *bluesky) checkPDS(client usrname.Client, handle string, r usrname.Result) usrname.Result {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     c.pdsHost,
+		Path:     "/xrpc/com.atproto.identity.resolveHandle",
+		RawQuery: url.Values{"handle": {handle}}.Encode(),
+	}
+	res, err := do(client, u)
+	if err != nil {
+		r.Status = usrname.UnknownStatus
+		if internal.IsTimeout(err) {
+			r.Message = fmt.Sprintf("%s timed out", c.Name())
+		} else {
+			r.Message = "Something went wrong"
+		}
+		return r
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		r.Status = usrname.Unavailable
+	case http.StatusBadRequest:
+		r.Status = usrname.Available
+	default:
+		r.Status = usrname.UnknownStatus
+		r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+	}
+	return r
+}
+
+func do(client usrname.Client, u url.URL) (*http.Response, error) {
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,318 @@
+package bluesky_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/bluesky"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = bluesky.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Bluesky"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		username string
+		link     string
+	}{
+		{"foobar", "https://bsky.app/profile/foobar.bsky.social"},
+		{"@FooBar.bsky.social", "https://bsky.app/profile/foobar.bsky.social"},
+		{"example.com", "https://bsky.app/profile/example.com"},
+	}
+	const template = "Link(%q), got %q, want %q"
+	for _, c := range cases {
+		if actual := checker.Link(c.username); actual != c.link {
+			t.Errorf(template, c.username, actual, c.link)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"bare",
+			"foo-bar",
+			noViolations,
+		}, {
+			"domain",
+			"sub.Example-1.com",
+			noViolations,
+		}, {
+			"shortdomain",
+			"ab.com",
+			noViolations,
+		}, {
+			"shortbare",
+			"ab",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 2},
+				},
+			},
+		}, {
+			"shortonpds",
+			"ab.BSKY.social",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 14},
+				},
+			},
+		}, {
+			"longbare",
+			strings.Repeat("a", 19),
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 19},
+				},
+			},
+		}, {
+			"exoticchars",
+			"foo_bar.com",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"emptylabel",
+			"foo..com",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{3, 5},
+				},
+			},
+		}, {
+			"hyphenlabel",
+			"foo.-bar.com",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{3, 5},
+				},
+			},
+		}, {
+			"longlabel",
+			strings.Repeat("a", 64) + ".com",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 64},
+				},
+			},
+		}, {
+			"numerictld",
+			"example.123",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{7, 11},
+				},
+			},
+		}, {
+			"disallowedtld",
+			"foo.local",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".local",
+				},
+			},
+		}, {
+			"toolong",
+			strings.Repeat(strings.Repeat("a", 63)+".", 4) + "com",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    253,
+					Actual: 259,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+// resolver holds TXT records by name; names absent from it do not exist.
+type resolver map[string][]string
+
+func (r resolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	if name == "_atproto.servfail.com" {
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name}
+	}
+	records, ok := r[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name}
+	}
+	return records, nil
+}
+
+// byURL responds with the status code and body associated with the host and
+// path of each request, and with 404 otherwise.
+type byURL map[string]struct {
+	code int
+	body string
+}
+
+func (m byURL) Do(req *http.Request) (*http.Response, error) {
+	r, ok := m[req.URL.Host+req.URL.Path]
+	if !ok {
+		r.code = http.StatusNotFound
+	}
+	res := http.Response{
+		StatusCode: r.code,
+		Body:       ioutil.NopCloser(strings.NewReader(r.body)),
+	}
+	return &res, nil
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	checker := bluesky.NewWithResolver(resolver{
+		"_atproto.dns.com":   {"v=spf1 -all", "did=did:plc:abc123"},
+		"_atproto.other.com": {"v=spf1 -all"},
+	})
+	web := byURL{
+		"https.com/.well-known/atproto-did":  {http.StatusOK, "did:plc:abc123\n"},
+		"other.com/.well-known/atproto-did":  {http.StatusOK, "<html>"},
+		"broken.com/.well-known/atproto-did": {http.StatusInternalServerError, ""},
+	}
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "privatehost",
+			username: "nas.lan",
+			client:   web,
+			status:   usrname.Invalid,
+		}, {
+			label:    "dns",
+			username: "@dns.com",
+			client:   nil,
+			status:   usrname.Unavailable,
+		}, {
+			label:    "dnsfailure",
+			username: "servfail.com",
+			client:   nil,
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "https",
+			username: "https.com",
+			client:   web,
+			status:   usrname.Unavailable,
+		}, {
+			label:    "nodid",
+			username: "other.com",
+			client:   web,
+			status:   usrname.Available,
+		}, {
+			label:    "notfound",
+			username: "free.com",
+			client:   web,
+			status:   usrname.Available,
+		}, {
+			label:    "other", // than 200, 404
+			username: "broken.com",
+			client:   web,
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "pdsok",
+			username: "taken",
+			client:   mockclient.WithStatusCode(http.StatusOK),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "pdsbadrequest",
+			username: "free.bsky.social",
+			client:   mockclient.WithStatusCode(http.StatusBadRequest),
+			status:   usrname.Available,
+		}, {
+			label:    "pdsother", // than 200, 400
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "free.com",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -0,0 +1,100 @@
+package usrname
+
+import (
+	"sync"
+	"time"
+)
+
+// minSweep is the number of entries below which a Cache never sweeps.
+const minSweep = 64
+
+// A Cache memoizes the results of checks for a while. Results are keyed by
+// checker and canonical username, so that usernames that designate the same
+// account (e.g. "Foo.Bar" and "foobar" on facebook) share them. Only results
+// that the network gave are kept: usernames are validated before the cache
+// is consulted, and results of unknown status are not kept either. Caches
+// are safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[cacheKey]cacheEntry
+	sweepAt int // number of entries at which to sweep expired ones
+}
+
+type cacheKey struct {
+	checker  string
+	username string // canonical
+}
+
+type cacheEntry struct {
+	result  Result
+	expires time.Time
+}
+
+// NewCache returns a Cache that keeps results for ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[cacheKey]cacheEntry),
+		sweepAt: minSweep,
+	}
+}
+
+// Check returns the result of checking username on checker through client,
+// from the cache if possible. Invalid usernames are passed on to checker,
+// whose checks report them without going to the network.
+func (c *Cache) Check(checker Checker, client Client, username string) Result {
+	if len(checker.Validate(username)) != 0 {
+		return checker.Check(client)(username)
+	}
+	key := cacheKey{checker.Name(), checker.Canonicalize(username)}
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		// Messages of valid usernames do not mention them; only
+		// Username is the caller's own.
+		r := e.result
+		r.Username = username
+		return r
+	}
+	delete(c.entries, key)
+	c.mu.Unlock()
+
+	r := checker.Check(client)(username)
+	if r.Status != UnknownStatus && r.Status != Invalid {
+		c.mu.Lock()
+		now := c.now()
+		if len(c.entries) >= c.sweepAt {
+			c.sweep(now)
+		}
+		c.entries[key] = cacheEntry{r, now.Add(c.ttl)}
+		c.mu.Unlock()
+	}
+	return r
+}
+
+// Len returns the number of results in c, including expired ones that have
+// yet to be evicted.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// sweep evicts the expired entries of c; callers must hold the lock. The
+// next sweep comes once the number of entries has doubled, which keeps the
+// cost of sweeping proportional to that of insertions.
+func (c *Cache) sweep(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.sweepAt = 2 * len(c.entries)
+	if c.sweepAt < minSweep {
+		c.sweepAt = minSweep
+	}
+}
@@ -0,0 +1,104 @@
+package usrname_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/custom"
+)
+
+// counter is a Checker that counts its checks of valid usernames and, like
+// site checkers, reports invalid ones without counting them.
+type counter struct {
+	*stub
+	checks int
+}
+
+func (c *counter) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) usrname.Result {
+		if len(c.Validate(username)) != 0 {
+			return usrname.Result{Username: username, Checker: c, Status: usrname.Invalid}
+		}
+		c.checks++
+		return c.stub.Check(client)(username)
+	}
+}
+
+func TestCache(t *testing.T) {
+	defer leaktest.Check(t)()
+	c := &counter{stub: newStub(t, "Counter", usrname.Unavailable)}
+	cache := usrname.NewCache(time.Hour)
+	for _, username := range []string{"FooBar", "foobar", "FOOBAR"} {
+		r := cache.Check(c, nil, username)
+		if r.Status != usrname.Unavailable || r.Username != username {
+			t.Errorf("Check(%q), got %v, want %q for %q", username, r, usrname.Unavailable, username)
+		}
+	}
+	if c.checks != 1 {
+		t.Errorf("got %d checks for equivalent usernames, want 1", c.checks)
+	}
+	cache.Check(c, nil, "baz")
+	if c.checks != 2 {
+		t.Errorf("got %d checks, want 2", c.checks)
+	}
+
+	unknown := &counter{stub: newStub(t, "Unknown", usrname.UnknownStatus)}
+	cache.Check(unknown, nil, "foobar")
+	cache.Check(unknown, nil, "foobar")
+	if unknown.checks != 2 {
+		t.Errorf("got %d checks of unknown status, want 2", unknown.checks)
+	}
+
+	expired := usrname.NewCache(0)
+	expired.Check(c, nil, "foobar")
+	expired.Check(c, nil, "foobar")
+	if c.checks != 4 {
+		t.Errorf("got %d checks after expiry, want 4", c.checks)
+	}
+}
+
+func TestCacheValidatesFirst(t *testing.T) {
+	defer leaktest.Check(t)()
+	v, err := custom.New(custom.Options{
+		Name:  "Lowercase",
+		Rules: usrname.Rules{Whitelist: &unicode.RangeTable{R16: []unicode.Range16{{'a', 'z', 1}}}},
+	})
+	if err != nil {
+		t.Fatalf("custom.New, unexpected error %v", err)
+	}
+	c := &counter{stub: &stub{v, usrname.Available}}
+	cache := usrname.NewCache(time.Hour)
+	cases := []struct {
+		username string
+		status   usrname.Status
+	}{
+		{"FOOBAR", usrname.Invalid}, // but canonically equal to "foobar"
+		{"foobar", usrname.Available},
+		{"FOOBAR", usrname.Invalid},
+		{"foobar", usrname.Available},
+	}
+	for _, k := range cases {
+		if r := cache.Check(c, nil, k.username); r.Status != k.status {
+			t.Errorf("Check(%q), got %q, want %q", k.username, r.Status, k.status)
+		}
+	}
+	if c.checks != 1 {
+		t.Errorf("got %d checks of valid usernames, want 1", c.checks)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	defer leaktest.Check(t)()
+	c := &counter{stub: newStub(t, "Counter", usrname.Unavailable)}
+	cache := usrname.NewCache(0)
+	for i := 0; i < 1000; i++ {
+		cache.Check(c, nil, fmt.Sprintf("user%d", i))
+	}
+	if n := cache.Len(); n > 64 {
+		t.Errorf("Len(), got %d expired entries, want at most 64", n)
+	}
+}
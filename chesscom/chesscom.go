@@ -0,0 +1,161 @@
+package chesscom
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type chesscom struct {
+	name            string
+	scheme          string
+	host            string
+	apiHost         string
+	illegalPrefixes []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+var chesscomImpl = chesscom{
+	name:            "Chess.com",
+	scheme:          "https",
+	host:            "www.chess.com",
+	apiHost:         "api.chess.com",
+	illegalPrefixes: []string{"-", "_"},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 3,
+	maxLength: 25,
+	metadata: usrname.Metadata{
+		Category:  usrname.Gaming,
+		Tags:      []string{"chess"},
+		Homepage:  "https://www.chess.com",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(chesscomImpl.name, &chesscomImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &chesscomImpl
+}
+
+func (s *chesscom) Name() string {
+	return s.name
+}
+
+func (s *chesscom) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *chesscom) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/member/" + username,
+	}
+	return u.String()
+}
+
+func (*chesscom) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *chesscom) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *chesscom) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+	}
+}
+
+// Usernames are case-insensitive; the API only knows them in lowercase.
+func (*chesscom) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://support.chess.com/en/articles/8557497-how-do-i-change-my-username
+func (v *chesscom) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *chesscom) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+// request goes through the published-data API, which, unlike profile pages,
+// answers with status code 404 for nonexistent players.
+func (c *chesscom) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.apiHost,
+		Path:   "/pub/player/" + c.Canonicalize(username),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,157 @@
+package chesscom_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/chesscom"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = chesscom.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Chess.com"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://www.chess.com/member/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"ab",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    3,
+					Actual: 2,
+				},
+			},
+		}, {
+			"specialchars",
+			"Foo-bar_42",
+			noViolations,
+		}, {
+			"hyphenprefix",
+			"-foobar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: "-",
+				},
+			},
+		}, {
+			"underscoreprefix",
+			"_foobar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: "_",
+				},
+			},
+		}, {
+			"toolong",
+			"01234567890123456789012345",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    25,
+					Actual: 26,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "ok",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusOK),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "notfound",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusNotFound),
+			status:   usrname.Available,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -1,6 +1,9 @@
 package usrname
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"time"
 
@@ -9,6 +12,9 @@ import (
 
 var timeout time.Duration
 
+// maxBodySize bounds the part of a response body that simpleClient keeps.
+const maxBodySize = 1 << 20
+
 func init() {
 	timeout = 1000 * time.Millisecond
 }
@@ -18,7 +24,8 @@ func NewClient() Client {
 }
 
 // Client implementations must close the Body of the Response (if non-nil)
-// before returning it.
+// before returning it. They may replace it with an in-memory copy of its
+// contents, for the benefit of checkers that inspect the body.
 type Client interface {
 	Do(*http.Request) (*http.Response, error)
 }
@@ -34,5 +41,11 @@ func (*simpleClient) Do(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 	defer res.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxBodySize))
+	if err != nil {
+		err := errors.Wrap(err, "usrname: client failed")
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
 	return res, nil
 }
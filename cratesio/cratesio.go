@@ -0,0 +1,172 @@
+package cratesio
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type cratesio struct {
+	name           string
+	scheme         string
+	host           string
+	userAgent      string
+	illegalPattern *regexp.Regexp
+	reserved       []string
+	whitelist      *unicode.RangeTable
+	minLength      int
+	maxLength      int
+	metadata       usrname.Metadata
+}
+
+var cratesioImpl = cratesio{
+	name:           "crates.io",
+	scheme:         "https",
+	host:           "crates.io",
+	userAgent:      "usrname (https://github.com/jubobs/usrname)",
+	illegalPattern: regexp.MustCompile(`^[^A-Za-z]`),
+	reserved: []string{
+		"alloc", "core", "proc_macro", "std", "test",
+		// Windows device names
+		"aux", "com1", "com2", "com3", "com4", "com5", "com6", "com7",
+		"com8", "com9", "con", "lpt1", "lpt2", "lpt3", "lpt4", "lpt5",
+		"lpt6", "lpt7", "lpt8", "lpt9", "nul", "prn",
+	},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 1,
+	maxLength: 64,
+	metadata: usrname.Metadata{
+		Category:  usrname.Packages,
+		Tags:      []string{"rust"},
+		Homepage:  "https://crates.io",
+		RulesURL:  "https://doc.rust-lang.org/cargo/reference/manifest.html#the-name-field",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(cratesioImpl.name, &cratesioImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &cratesioImpl
+}
+
+func (s *cratesio) Name() string {
+	return s.name
+}
+
+func (s *cratesio) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *cratesio) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/crates/" + username,
+	}
+	return u.String()
+}
+
+func (v *cratesio) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *cratesio) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *cratesio) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:      v.minLength,
+		MaxLength:      v.maxLength,
+		Whitelist:      v.whitelist,
+		IllegalPattern: v.illegalPattern.String(),
+		Reserved:       append([]string(nil), v.reserved...),
+	}
+}
+
+// Crate names are case-insensitive, and hyphens and underscores are
+// interchangeable.
+func (*cratesio) Canonicalize(username string) string {
+	return strings.Replace(strings.ToLower(username), "_", "-", -1)
+}
+
+// See https://doc.rust-lang.org/cargo/reference/manifest.html#the-name-field
+func (v *cratesio) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckNotReserved(v.reserved),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *cratesio) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+func (c *cratesio) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   "/api/v1/crates/" + username,
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	req.Header.Add("User-Agent", c.userAgent) // required by the crawler policy
+	return req
+}
@@ -0,0 +1,185 @@
+package cratesio_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/cratesio"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = cratesio.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "crates.io"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://crates.io/crates/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"specialchars",
+			"foo-bar_baz",
+			noViolations,
+		}, {
+			"period",
+			"foo.bar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"digitprefix",
+			"2d",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 1},
+				},
+			},
+		}, {
+			"devicename",
+			"NUL",
+			[]usrname.Violation{
+				&usrname.Reserved{
+					Word: "nul",
+				},
+			},
+		}, {
+			"toolong",
+			"a1234567890123456789012345678901234567890123456789012345678901234",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    64,
+					Actual: 65,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		username  string
+		canonical string
+	}{
+		{"foobar", "foobar"},
+		{"Foo_Bar", "foo-bar"},
+		{"foo-bar", "foo-bar"},
+	}
+	const template = "Canonicalize(%q), got %q, want %q"
+	for _, c := range cases {
+		if actual := checker.Canonicalize(c.username); actual != c.canonical {
+			t.Errorf(template, c.username, actual, c.canonical)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "notfound",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusNotFound),
+			status:   usrname.Available,
+		}, {
+			label:    "ok",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusOK),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusForbidden),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
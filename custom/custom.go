@@ -0,0 +1,158 @@
+package custom
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+// Options describes a Validator for usernames of one's own, which no site
+// needs to be asked about.
+type Options struct {
+	Name string
+	// LinkTemplate, if not empty, is the fmt template from which links are
+	// made, e.g. "https://example.com/@%s". It must contain the verb %s,
+	// which stands for the username, exactly once, and no other verb; literal
+	// percent signs are written %%.
+	LinkTemplate string
+	// CaseSensitive tells whether usernames that differ only in case
+	// designate different accounts.
+	CaseSensitive bool
+	// Metadata, which may be left zero, is what Metadata returns.
+	Metadata usrname.Metadata
+	Rules    usrname.Rules
+}
+
+type custom struct {
+	name           string
+	linkTemplate   string
+	caseSensitive  bool
+	metadata       usrname.Metadata
+	rules          usrname.Rules
+	illegalPattern *regexp.Regexp
+}
+
+// New returns a Validator that enforces the rules of o. It fails if those
+// rules are inconsistent, if their illegal pattern does not compile or if
+// the link template is malformed.
+func New(o Options) (usrname.Validator, error) {
+	if o.Name == "" {
+		return nil, errors.New("custom: missing name")
+	}
+	if o.Rules.MinLength < 0 {
+		return nil, fmt.Errorf("custom: negative minimum length %d", o.Rules.MinLength)
+	}
+	if o.Rules.MaxLength != 0 && o.Rules.MaxLength < o.Rules.MinLength {
+		const templ = "custom: maximum length %d less than minimum length %d"
+		return nil, fmt.Errorf(templ, o.Rules.MaxLength, o.Rules.MinLength)
+	}
+	if o.LinkTemplate != "" {
+		verbs := strings.Replace(o.LinkTemplate, "%%", "", -1)
+		if strings.Count(verbs, "%") != 1 || !strings.Contains(verbs, "%s") {
+			const templ = "custom: link template %q must contain %%s exactly once and no other verb"
+			return nil, fmt.Errorf(templ, o.LinkTemplate)
+		}
+	}
+	v := custom{
+		name:          o.Name,
+		linkTemplate:  o.LinkTemplate,
+		caseSensitive: o.CaseSensitive,
+		metadata:      *o.Metadata.Copy(),
+		rules:         copyRules(o.Rules),
+	}
+	if o.Rules.IllegalPattern != "" {
+		re, err := regexp.Compile(o.Rules.IllegalPattern)
+		if err != nil {
+			return nil, fmt.Errorf("custom: %v", err)
+		}
+		v.illegalPattern = re
+	}
+	return &v, nil
+}
+
+func (s *custom) Name() string {
+	return s.name
+}
+
+func (s *custom) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+// Link returns the empty string if no link template was given.
+func (s *custom) Link(username string) string {
+	if s.linkTemplate == "" {
+		return ""
+	}
+	return fmt.Sprintf(s.linkTemplate, username)
+}
+
+func (v *custom) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *custom) Whitelist() *unicode.RangeTable {
+	return v.rules.Whitelist
+}
+
+func (v *custom) Rules() *usrname.Rules {
+	r := copyRules(v.rules)
+	return &r
+}
+
+// copyRules returns a copy of r that shares no slice with it.
+func copyRules(r usrname.Rules) usrname.Rules {
+	r.IllegalPrefixes = append([]string(nil), r.IllegalPrefixes...)
+	r.IllegalSuffixes = append([]string(nil), r.IllegalSuffixes...)
+	r.IllegalSubstrings = append([]string(nil), r.IllegalSubstrings...)
+	r.Reserved = append([]string(nil), r.Reserved...)
+	return r
+}
+
+// Usernames are case-insensitive unless specified otherwise.
+func (v *custom) Canonicalize(username string) string {
+	if v.caseSensitive {
+		return username
+	}
+	return strings.ToLower(username)
+}
+
+// Validate checks username against those of the rules that were given.
+func (v *custom) Validate(username string) []usrname.Violation {
+	r := &v.rules
+	fs := []func(string) usrname.Violation{
+		internal.CheckLongerThan(r.MinLength),
+	}
+	if r.Whitelist != nil {
+		fs = append(fs, internal.CheckOnlyContains(r.Whitelist))
+	}
+	if len(r.IllegalPrefixes) != 0 {
+		fs = append(fs, internal.CheckIllegalPrefixes(r.IllegalPrefixes))
+	}
+	for _, s := range r.IllegalSubstrings {
+		fs = append(fs, internal.CheckIllegalSubstring(s))
+	}
+	if v.illegalPattern != nil {
+		fs = append(fs, internal.CheckNotMatches(v.illegalPattern))
+	}
+	if len(r.IllegalSuffixes) != 0 {
+		fs = append(fs, internal.CheckIllegalSuffixes(r.IllegalSuffixes))
+	}
+	if len(r.Reserved) != 0 {
+		fs = append(fs, internal.CheckNotReserved(r.Reserved))
+	}
+	if r.MaxLength != 0 {
+		fs = append(fs, internal.CheckShorterThan(r.MaxLength))
+	}
+	vv := []usrname.Violation{}
+	for _, f := range fs {
+		if v := f(username); v != nil {
+			vv = append(vv, v)
+		}
+	}
+	return vv
+}
@@ -0,0 +1,203 @@
+package custom_test
+
+import (
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/custom"
+)
+
+var options = custom.Options{
+	Name:         "ACME",
+	LinkTemplate: "https://acme.example/@%s",
+	Rules: usrname.Rules{
+		MinLength: 3,
+		MaxLength: 12,
+		Whitelist: &unicode.RangeTable{
+			R16: []unicode.Range16{
+				{'-', '.', 1},
+				{'0', '9', 1},
+				{'_', '_', 1},
+				{'a', 'z', 1},
+			},
+		},
+		IllegalPrefixes:   []string{"-", "."},
+		IllegalSuffixes:   []string{"."},
+		IllegalSubstrings: []string{"--"},
+		IllegalPattern:    `^[0-9]+$`,
+		Reserved:          []string{"admin", "support"},
+	},
+}
+
+func TestNew(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		label   string
+		options custom.Options
+	}{
+		{"noname", custom.Options{}},
+		{"negative", custom.Options{Name: "x", Rules: usrname.Rules{MinLength: -1}}},
+		{"inverted", custom.Options{Name: "x", Rules: usrname.Rules{MinLength: 4, MaxLength: 3}}},
+		{"badpattern", custom.Options{Name: "x", Rules: usrname.Rules{IllegalPattern: "("}}},
+		{"noverb", custom.Options{Name: "x", LinkTemplate: "https://acme.example/"}},
+		{"escapedverb", custom.Options{Name: "x", LinkTemplate: "https://acme.example/%%s"}},
+		{"twoverbs", custom.Options{Name: "x", LinkTemplate: "https://%s.acme.example/%s"}},
+		{"otherverb", custom.Options{Name: "x", LinkTemplate: "https://acme.example/%d/%s"}},
+		{"trailingpercent", custom.Options{Name: "x", LinkTemplate: "https://acme.example/%s%"}},
+	}
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if _, err := custom.New(c.options); err == nil {
+				t.Errorf("New(%+v), got no error, want one", c.options)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	v, _ := custom.New(options)
+	const expected = "ACME"
+	if actual := v.Name(); actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	v, _ := custom.New(options)
+	const template = "got %q, want %q"
+	if actual, expected := v.Link("foobar"), "https://acme.example/@foobar"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	v, _ = custom.New(custom.Options{Name: "nolink"})
+	if actual, expected := v.Link("foobar"), ""; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	v, _ = custom.New(custom.Options{Name: "percent", LinkTemplate: "https://acme.example/100%%/%s"})
+	if actual, expected := v.Link("foobar"), "https://acme.example/100%/foobar"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	v, _ := custom.New(options)
+	if !usrname.Equivalent(v, "FooBar", "foobar") {
+		t.Errorf("Equivalent(%q, %q), got false, want true", "FooBar", "foobar")
+	}
+	o := options
+	o.CaseSensitive = true
+	v, _ = custom.New(o)
+	if usrname.Equivalent(v, "FooBar", "foobar") {
+		t.Errorf("Equivalent(%q, %q), got true, want false", "FooBar", "foobar")
+	}
+}
+
+func TestRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	v, _ := custom.New(options)
+	if actual := v.Rules(); !reflect.DeepEqual(*actual, options.Rules) {
+		t.Errorf("Rules(), got %+v, want %+v", actual, options.Rules)
+	}
+	if _, err := v.Rules().Regexp(); err != nil {
+		t.Errorf("Regexp(), unexpected error %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	v, _ := custom.New(options)
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"ab",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    3,
+					Actual: 2,
+				},
+			},
+		}, {
+			"valid",
+			"foo.bar-42",
+			noViolations,
+		}, {
+			"uppercase",
+			"fooBar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: v.Whitelist(),
+				},
+			},
+		}, {
+			"prefix",
+			".foobar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"substring",
+			"foo--bar",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: "--",
+					At:      []int{3, 5},
+				},
+			},
+		}, {
+			"pattern",
+			"1234",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: v.IllegalPattern().String(),
+					At:      []int{0, 4},
+				},
+			},
+		}, {
+			"suffix",
+			"foobar.",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"reserved",
+			"support",
+			[]usrname.Violation{
+				&usrname.Reserved{
+					Word: "support",
+				},
+			},
+		}, {
+			"toolong",
+			"abcdefghijklm",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    12,
+					Actual: 13,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := v.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
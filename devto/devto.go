@@ -0,0 +1,174 @@
+package devto
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type devto struct {
+	name      string
+	scheme    string
+	host      string
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var devtoImpl = devto{
+	name:   "Dev.to",
+	scheme: "https",
+	host:   "dev.to",
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 2,
+	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Code,
+		Tags:      []string{"blogging", "community"},
+		Homepage:  "https://dev.to",
+		RulesURL:  "https://github.com/forem/forem/blob/main/app/models/user.rb",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(devtoImpl.name, &devtoImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &devtoImpl
+}
+
+func (s *devto) Name() string {
+	return s.name
+}
+
+func (s *devto) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *devto) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/" + username,
+	}
+	return u.String()
+}
+
+func (*devto) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *devto) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *devto) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Usernames are stored in lowercase.
+func (*devto) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://github.com/forem/forem/blob/main/app/models/user.rb
+func (v *devto) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *devto) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		// Users and organizations share the namespace of top-level paths
+		// but not API endpoints; the name is available only if neither
+		// knows of it.
+		for _, req := range []*http.Request{c.userRequest(username), c.orgRequest(username)} {
+			res, err := client.Do(req)
+			if err != nil {
+				r.Status = usrname.UnknownStatus
+				if internal.IsTimeout(err) {
+					r.Message = fmt.Sprintf("%s timed out", c.Name())
+				} else {
+					r.Message = "Something went wrong"
+				}
+				return
+			}
+			switch res.StatusCode {
+			case http.StatusOK:
+				r.Status = usrname.Unavailable
+				return
+			case http.StatusNotFound:
+			default:
+				r.Status = usrname.UnknownStatus
+				r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+				return
+			}
+		}
+		r.Status = usrname.Available
+		return
+	}
+}
+
+func (c *devto) userRequest(username string) *http.Request {
+	u := url.URL{
+		Scheme:   c.scheme,
+		Host:     c.host,
+		Path:     "/api/users/by_username",
+		RawQuery: url.Values{"url": {username}}.Encode(),
+	}
+	return newRequest(u)
+}
+
+func (c *devto) orgRequest(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   "/api/organizations/" + username,
+	}
+	return newRequest(u)
+}
+
+func newRequest(u url.URL) *http.Request {
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	req.Header.Set("Accept", "application/vnd.forem.api-v1+json")
+	return req
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/jubobs/usrname"
@@ -20,6 +21,7 @@ type disqus struct {
 	whitelist     *unicode.RangeTable
 	minLength     int
 	maxLength     int
+	metadata      usrname.Metadata
 }
 
 var disqusImpl = disqus{
@@ -38,6 +40,12 @@ var disqusImpl = disqus{
 	},
 	minLength: 2,
 	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"comments"},
+		Homepage:  "https://disqus.com",
+		Probeable: true,
+	},
 }
 
 func init() {
@@ -54,6 +62,10 @@ func (s *disqus) Name() string {
 	return s.name
 }
 
+func (s *disqus) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
 func (*disqus) Link(username string) string {
 	u := url.URL{
 		Scheme: disqusImpl.scheme,
@@ -71,6 +83,21 @@ func (v *disqus) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *disqus) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: []string{v.illegalPrefix},
+		IllegalSuffixes: []string{v.illegalSuffix},
+	}
+}
+
+// Usernames are case-insensitive.
+func (*disqus) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
 // See https://help.disqus.com/en/managing-your-account/disqus-username-rules
 func (v *disqus) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
@@ -124,6 +124,22 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	expected := &usrname.Rules{
+		MinLength:       2,
+		MaxLength:       30,
+		Whitelist:       checker.Whitelist(),
+		IllegalPrefixes: []string{"_"},
+		IllegalSuffixes: []string{"_"},
+	}
+	actual := checker.Rules()
+	if !reflect.DeepEqual(actual, expected) {
+		template := "got %#v, want %#v"
+		t.Errorf(template, actual, expected)
+	}
+}
+
 func TestCheck(t *testing.T) {
 	defer leaktest.Check(t)()
 
@@ -0,0 +1,164 @@
+package dockerhub
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type dockerhub struct {
+	name      string
+	scheme    string
+	host      string
+	apiHost   string
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var dockerhubImpl = dockerhub{
+	name:    "Docker Hub",
+	scheme:  "https",
+	host:    "hub.docker.com",
+	apiHost: "hub.docker.com",
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'0', '9', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 4,
+	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Packages,
+		Tags:      []string{"containers"},
+		Homepage:  "https://hub.docker.com",
+		RulesURL:  "https://docs.docker.com/docker-id/",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(dockerhubImpl.name, &dockerhubImpl); err != nil {
+		panic(err)
+	}
+}
+
+// New returns the Checker for Docker Hub namespaces, which belong either to
+// users or to organizations.
+func New() usrname.Checker {
+	return &dockerhubImpl
+}
+
+func (s *dockerhub) Name() string {
+	return s.name
+}
+
+func (s *dockerhub) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *dockerhub) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/u/" + username,
+	}
+	return u.String()
+}
+
+func (*dockerhub) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *dockerhub) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *dockerhub) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Namespaces are lowercase, but Docker IDs are accepted regardless of case
+// when signing in.
+func (*dockerhub) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://docs.docker.com/docker-id/
+func (v *dockerhub) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *dockerhub) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		// Users and organizations share namespaces but not endpoints; the
+		// namespace is available only if neither knows of it.
+		for _, kind := range []string{"users", "orgs"} {
+			req := c.request(kind, username)
+			res, err := client.Do(req)
+			if err != nil {
+				r.Status = usrname.UnknownStatus
+				if internal.IsTimeout(err) {
+					r.Message = fmt.Sprintf("%s timed out", c.Name())
+				} else {
+					r.Message = "Something went wrong"
+				}
+				return
+			}
+			switch res.StatusCode {
+			case http.StatusOK:
+				r.Status = usrname.Unavailable
+				return
+			case http.StatusNotFound:
+			default:
+				r.Status = usrname.UnknownStatus
+				r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+				return
+			}
+		}
+		r.Status = usrname.Available
+		return
+	}
+}
+
+// request looks username up among accounts of the given kind ("users" or
+// "orgs") through the public v2 API.
+func (c *dockerhub) request(kind string, username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.apiHost,
+		Path:   "/v2/" + kind + "/" + username + "/",
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,298 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+// Resolver looks up DNS NS records; *net.Resolver (Go 1.8 and later)
+// satisfies it.
+type Resolver interface {
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+}
+
+// dnsTimeout bounds DNS lookups, as the client's timeout bounds requests.
+const dnsTimeout = 1000 * time.Millisecond
+
+// rdapServices maps the TLDs checked by default to the base URLs of their
+// registries' RDAP services, as listed in IANA's bootstrap registry.
+var rdapServices = map[string]string{
+	"com": "https://rdap.verisign.com/com/v1",
+	"dev": "https://pubapi.registry.google/rdap",
+	"io":  "https://rdap.identitydigital.services/rdap",
+}
+
+// domain checks whether usernames, as second-level domains under tld, are
+// registered. Internationalized names may be given either as U-labels (e.g.
+// "bücher") or as A-labels (e.g. "xn--bcher-kva"), which designate the same
+// domain; the IDNA tables, which rule out a few more code points than
+// Validate does, are left to the registry.
+type domain struct {
+	name           string
+	tld            string // in ASCII
+	rdap           url.URL
+	resolver       Resolver
+	illegalPrefix  string
+	illegalSuffix  string
+	illegalPattern *regexp.Regexp
+	whitelist      *unicode.RangeTable
+	minLength      int
+	maxLength      int
+	metadata       usrname.Metadata
+}
+
+var defaults = make(map[string]*domain)
+
+func init() {
+	tlds := make([]string, 0, len(rdapServices))
+	for tld := range rdapServices {
+		tlds = append(tlds, tld)
+	}
+	sort.Strings(tlds)
+	for _, tld := range tlds {
+		c, err := newDomain(tld, rdapServices[tld], nil)
+		if err != nil {
+			panic(err)
+		}
+		if err := usrname.Register(c.name, c); err != nil {
+			panic(err)
+		}
+		defaults[tld] = c
+	}
+}
+
+func newDomain(tld string, rdapBaseURL string, r Resolver) (*domain, error) {
+	tld = strings.ToLower(strings.TrimPrefix(tld, "."))
+	u, err := url.Parse(rdapBaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("domain: invalid RDAP base URL %q", rdapBaseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if r == nil {
+		r = internal.Resolver{}
+	}
+	c := domain{
+		name:          "." + tld,
+		rdap:          *u,
+		resolver:      r,
+		illegalPrefix: "-",
+		illegalSuffix: "-",
+		// hyphens in the third and fourth positions, which RFC 5891
+		// reserves for A-labels and the like, save in A-labels
+		illegalPattern: regexp.MustCompile(`^(?:[^Xx].|[Xx][^Nn])--`),
+		whitelist:      whitelist,
+		minLength:      1,
+		maxLength:      63,
+		metadata: usrname.Metadata{
+			Category:  usrname.Domains,
+			Tags:      []string{"dns", "rdap"},
+			RulesURL:  "https://www.rfc-editor.org/rfc/rfc5891#section-4.2.3.1",
+			Probeable: true,
+		},
+	}
+	if vv := c.Validate(tld); len(vv) != 0 {
+		return nil, fmt.Errorf("domain: invalid TLD %q", tld)
+	}
+	c.tld = c.Canonicalize(tld)
+	c.metadata.Homepage = "https://www.iana.org/domains/root/db/" + c.tld + ".html"
+	return &c, nil
+}
+
+// New returns the Checker for second-level domains under tld (e.g. "com"),
+// which must be one of the TLDs checked by default.
+func New(tld string) (usrname.Checker, error) {
+	c, ok := defaults[strings.ToLower(strings.TrimPrefix(tld, "."))]
+	if !ok {
+		return nil, fmt.Errorf("domain: no default RDAP service for %q", tld)
+	}
+	return c, nil
+}
+
+// NewInstance returns a Checker, named after tld, that asks the RDAP service
+// at rdapBaseURL (e.g. "https://rdap.example/rdap") whether domains under tld
+// are registered and, failing an answer, looks their name servers up through
+// r, which can only tell that a domain is taken. A nil r stands for the
+// system's resolver.
+func NewInstance(tld string, rdapBaseURL string, r Resolver) (usrname.Checker, error) {
+	return newDomain(tld, rdapBaseURL, r)
+}
+
+// Register registers a Checker for second-level domains under tld, as
+// returned by NewInstance, and returns it.
+func Register(tld string, rdapBaseURL string, r Resolver) (usrname.Checker, error) {
+	c, err := newDomain(tld, rdapBaseURL, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := usrname.Register(c.name, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// whitelist holds the runes of LDH labels and those of U-labels.
+var whitelist = internal.Union(
+	&unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'a', 'z', 1},
+		},
+	},
+	unicode.L,
+	unicode.M,
+	unicode.Nd,
+)
+
+// fqdn returns the domain that username designates, in ASCII.
+func (s *domain) fqdn(username string) string {
+	return s.Canonicalize(username) + "." + s.tld
+}
+
+func (s *domain) Name() string {
+	return s.name
+}
+
+func (s *domain) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *domain) Link(username string) string {
+	u := url.URL{
+		Scheme: "https",
+		Host:   s.fqdn(username),
+	}
+	return u.String()
+}
+
+func (v *domain) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *domain) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *domain) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: []string{v.illegalPrefix},
+		IllegalSuffixes: []string{v.illegalSuffix},
+		IllegalPattern:  v.illegalPattern.String(),
+		IDNA:            true,
+	}
+}
+
+// Domain names are case-insensitive, and U-labels designate the same domains
+// as their A-labels, which are canonical.
+func (*domain) Canonicalize(username string) string {
+	if a, err := internal.ToASCII(username); err == nil {
+		return a
+	}
+	return strings.ToLower(username)
+}
+
+// See https://www.rfc-editor.org/rfc/rfc5891#section-4.2.3.1 and
+// https://www.rfc-editor.org/rfc/rfc5891#section-5.4
+func (v *domain) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefix(v.illegalPrefix),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckIllegalSuffix(v.illegalSuffix),
+		internal.CheckIDNA(),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *domain) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		// Only the registry knows whether a domain is free; DNS cannot tell
+		// unregistered domains from registered ones that are not delegated
+		// (e.g. on hold).
+		fqdn := c.fqdn(username)
+		req := c.request(fqdn)
+		if res, err := client.Do(req); err == nil {
+			switch res.StatusCode {
+			case http.StatusOK:
+				r.Status = usrname.Unavailable
+				return
+			case http.StatusNotFound:
+				r.Status = usrname.Available
+				return
+			}
+		}
+		return c.checkDNS(fqdn, r)
+	}
+}
+
+// checkDNS looks for the name servers of fqdn, which every delegated domain
+// has; their absence proves nothing. (The standard library cannot look SOA
+// records up.)
+func (c *domain) checkDNS(fqdn string, r usrname.Result) usrname.Result {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+	defer cancel()
+	ns, err := c.resolver.LookupNS(ctx, fqdn)
+	if internal.IsNotFound(err) {
+		r.Status = usrname.UnknownStatus
+		r.Message = fmt.Sprintf("no RDAP answer and no name servers for %s", fqdn)
+		return r
+	}
+	switch {
+	case err != nil:
+		r.Status = usrname.UnknownStatus
+		if internal.IsTimeout(err) {
+			r.Message = fmt.Sprintf("%s timed out", c.Name())
+		} else {
+			r.Message = fmt.Sprintf("DNS lookup failed: %v", err)
+		}
+	case len(ns) != 0:
+		r.Status = usrname.Unavailable
+	default:
+		r.Status = usrname.UnknownStatus
+		r.Message = fmt.Sprintf("no RDAP answer and no name servers for %s", fqdn)
+	}
+	return r
+}
+
+// request asks the registry's RDAP service about fqdn; it answers with
+// status code 404 for domains that are not registered.
+func (c *domain) request(fqdn string) *http.Request {
+	u := c.rdap
+	u.Path += "/domain/" + fqdn
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+	return req
+}
@@ -0,0 +1,385 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/domain"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker, _ = domain.New("com")
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = ".com"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "FooBar"
+	const expected = "https://foobar.com"
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLinkULabel(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "Bücher"
+	const expected = "https://xn--bcher-kva.com"
+	if actual := checker.Link(username); actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	const template = "Canonicalize(%q), got %q, want %q"
+	cases := map[string]string{
+		"FooBar":        "foobar",
+		"Bücher":        "xn--bcher-kva",
+		"XN--BCHER-KVA": "xn--bcher-kva",
+		"münchen":       "xn--mnchen-3ya",
+		"例え":            "xn--r8jz45g",
+	}
+	for username, expected := range cases {
+		if actual := checker.Canonicalize(username); actual != expected {
+			t.Errorf(template, username, actual, expected)
+		}
+	}
+	if !usrname.Equivalent(checker, "bücher", "xn--bcher-kva") {
+		t.Errorf("Equivalent(%q, %q), got false, want true", "bücher", "xn--bcher-kva")
+	}
+}
+
+func TestNew(t *testing.T) {
+	defer leaktest.Check(t)()
+	for _, tld := range []string{"com", ".io", "DEV"} {
+		c, err := domain.New(tld)
+		if err != nil {
+			t.Fatalf("New(%q), unexpected error %v", tld, err)
+		}
+		expected := "." + strings.ToLower(strings.TrimPrefix(tld, "."))
+		if actual, _ := usrname.CheckerFor(expected); actual != c {
+			t.Errorf("CheckerFor(%q), got %v, want %v", expected, actual, c)
+		}
+	}
+	if _, err := domain.New("example"); err == nil {
+		t.Errorf("New(%q), got no error, want one", "example")
+	}
+}
+
+func TestNewInstance(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := domain.NewInstance(".Example", "https://rdap.example/", nil)
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	const template = "got %q, want %q"
+	if actual, expected := c.Name(), ".example"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := c.Link("foobar"), "https://foobar.example"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if _, err := domain.NewInstance("example", "rdap.example", nil); err == nil {
+		t.Errorf("NewInstance with relative URL, got no error, want one")
+	}
+	if _, err := domain.NewInstance("-example", "https://rdap.example", nil); err == nil {
+		t.Errorf("NewInstance with invalid TLD, got no error, want one")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := domain.Register("test", "https://rdap.example", nil)
+	if err != nil {
+		t.Fatalf("Register(%q), unexpected error %v", "test", err)
+	}
+	if actual, _ := usrname.CheckerFor(".test"); actual != c {
+		t.Errorf("CheckerFor(%q), got %v, want %v", ".test", actual, c)
+	}
+	if _, err := domain.Register("com", "https://rdap.example", nil); err == nil {
+		t.Errorf("Register(%q), got no error, want one", "com")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"ldh",
+			"Foo-bar-42",
+			noViolations,
+		}, {
+			"alabel",
+			"xn--bcher-kva",
+			noViolations,
+		}, {
+			"alabelupper",
+			"XN--BCHER-KVA",
+			noViolations,
+		}, {
+			"ulabel",
+			"Bücher",
+			noViolations,
+		}, {
+			"ulabelmark",
+			"\u0301bcher",
+			[]usrname.Violation{
+				&usrname.InvalidLabel{
+					Reason: "U-label starting with a combining mark",
+				},
+			},
+		}, {
+			"ulabelsymbol",
+			"b☃cher",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{1},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"ulabeltoolong", // 59 runes, but 66 octets as an A-label
+			"ü" + strings.Repeat("a", 58),
+			[]usrname.Violation{
+				&usrname.InvalidLabel{
+					Reason: "A-label longer than 63 octets",
+				},
+			},
+		}, {
+			"alabeltruncated",
+			"xn--bcher-kv",
+			[]usrname.Violation{
+				&usrname.InvalidLabel{
+					Reason: "punycode: truncated input",
+				},
+			},
+		}, {
+			"alabelascii",
+			"xn--bcher-",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: "-",
+				},
+				&usrname.InvalidLabel{
+					Reason: "A-label of an ASCII label",
+				},
+			},
+		}, {
+			"alabelsymbol", // encodes "b☃cher"
+			"xn--bcher-jb1c",
+			[]usrname.Violation{
+				&usrname.InvalidLabel{
+					Reason: "U-label with a disallowed code point",
+				},
+			},
+		}, {
+			"period",
+			"foo.bar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"hyphenprefix",
+			"-foobar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: "-",
+				},
+			},
+		}, {
+			"hyphensuffix",
+			"foobar-",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: "-",
+				},
+			},
+		}, {
+			"reservedhyphens",
+			"ab--cd",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 4},
+				},
+			},
+		}, {
+			"toolong",
+			strings.Repeat("a", 64),
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    63,
+					Actual: 64,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+// resolver holds NS records by name; names absent from it do not exist.
+type resolver map[string][]string
+
+func (r resolver) LookupNS(_ context.Context, name string) ([]*net.NS, error) {
+	switch name {
+	case "servfail.test":
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name}
+	case "slow.test":
+		return nil, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true}
+	}
+	hosts, ok := r[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name}
+	}
+	ns := make([]*net.NS, len(hosts))
+	for i, h := range hosts {
+		ns[i] = &net.NS{Host: h}
+	}
+	return ns, nil
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rdap/domain/taken.test":
+			w.Header().Set("Content-Type", "application/rdap+json")
+			w.Write([]byte(`{"objectClassName":"domain","ldhName":"taken.test"}`))
+		case "/rdap/domain/free.test":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+	instance, err := domain.NewInstance("test", ts.URL+"/rdap/", resolver{
+		"delegated.test": {"ns1.example.net.", "ns2.example.net."},
+		"nodata.test":    {},
+	})
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "-obviously-invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "registered",
+			username: "Taken",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "notfound",
+			username: "free",
+			client:   usrname.NewClient(),
+			status:   usrname.Available,
+		}, {
+			label:    "dnsdelegated", // after status code 429
+			username: "delegated",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "dnsnxdomain", // possibly registered but on hold
+			username: "nonexistent",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "dnsnodata",
+			username: "nodata",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "dnsfailure",
+			username: "servfail",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "dnstimeout",
+			username: "slow",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror", // falls back on DNS
+			username: "delegated",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "timeouterror", // falls back on DNS
+			username: "servfail",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := instance.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
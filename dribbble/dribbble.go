@@ -0,0 +1,178 @@
+package dribbble
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type dribbble struct {
+	name      string
+	scheme    string
+	host      string
+	notFound  string // marks the soft-404 pages served for nonexistent users
+	found     string // marks the pages of existing profiles
+	reserved  []string
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var dribbbleImpl = dribbble{
+	name:     "Dribbble",
+	scheme:   "https",
+	host:     "dribbble.com",
+	notFound: `<div class="error-404`,
+	found:    `class="masthead-profile-name"`,
+	// top-level paths of the site's own pages
+	reserved: []string{
+		"about", "account", "designers", "jobs", "learn", "pro", "search",
+		"session", "shots", "signup", "stories", "tags", "teams",
+	},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 2,
+	maxLength: 20,
+	metadata: usrname.Metadata{
+		Category:  usrname.Creative,
+		Tags:      []string{"design", "portfolio"},
+		Homepage:  "https://dribbble.com",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(dribbbleImpl.name, &dribbbleImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &dribbbleImpl
+}
+
+func (s *dribbble) Name() string {
+	return s.name
+}
+
+func (s *dribbble) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *dribbble) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/" + username,
+	}
+	return u.String()
+}
+
+func (*dribbble) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *dribbble) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *dribbble) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+		Reserved:  append([]string(nil), v.reserved...),
+	}
+}
+
+// Usernames are case-insensitive.
+func (*dribbble) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://help.dribbble.com/hc/en-us/articles/115003826908-Changing-your-username
+func (v *dribbble) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckNotReserved(v.reserved),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *dribbble) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Pages are served for more paths than there are profiles; only
+			// the markup tells them apart, and its markers come from
+			// hand-written fixtures, not from Dribbble, so they only make the
+			// message more specific.
+			body, err := internal.ReadBody(res)
+			notFound := bytes.Contains(body, []byte(c.notFound))
+			found := bytes.Contains(body, []byte(c.found))
+			r.Status = usrname.UnknownStatus
+			switch {
+			case err != nil:
+				r.Message = "unexpected response body"
+			case notFound && !found:
+				r.Message = "error page, going by unverified markup"
+			case found && !notFound:
+				r.Message = "profile page, going by unverified markup"
+			default:
+				r.Message = "no profile data in page"
+			}
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+func (c *dribbble) request(username string) *http.Request {
+	req, err := http.NewRequest("GET", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
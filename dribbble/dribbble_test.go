@@ -0,0 +1,182 @@
+package dribbble_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/dribbble"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = dribbble.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Dribbble"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://dribbble.com/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"a",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    2,
+					Actual: 1,
+				},
+			},
+		}, {
+			"valid",
+			"Jane-Doe_42",
+			noViolations,
+		}, {
+			"period",
+			"jane.doe",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{4},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"reserved",
+			"Shots",
+			[]usrname.Violation{
+				&usrname.Reserved{
+					Word: "shots",
+				},
+			},
+		}, {
+			"toolong",
+			"aaaaaaaaaaaaaaaaaaaaa",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    20,
+					Actual: 21,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+		message  string // if not empty
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "profile",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/profile.http"),
+			status:   usrname.UnknownStatus,
+			message:  "profile page, going by unverified markup",
+		}, {
+			label:    "softnotfound",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/softnotfound.http"),
+			status:   usrname.UnknownStatus,
+			message:  "error page, going by unverified markup",
+		}, {
+			label:    "bothmarkers",
+			username: "janedoe",
+			client: mockclient.NewScript().On("", "", mockclient.Response{
+				StatusCode: http.StatusOK,
+				Body:       `<div class="error-404"><h1 class="masthead-profile-name">Jane</h1>`,
+			}),
+			status: usrname.UnknownStatus,
+		}, {
+			label:    "notfound",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/notfound.http"),
+			status:   usrname.Available,
+		}, {
+			label:    "page",
+			username: "janedoe",
+			client:   mockclient.WithResponseFile("testdata/page.http"),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+			if c.message != "" && res.Message != c.message {
+				t.Errorf("Check(%q), got message %q, want %q", c.username, res.Message, c.message)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
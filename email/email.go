@@ -0,0 +1,52 @@
+package email
+
+import (
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/custom"
+)
+
+// atext lists the characters of atoms, plus the period that separates them.
+var atext = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{'!', '!', 1},
+		{'#', '\'', 1},
+		{'*', '+', 1},
+		{'-', '9', 1}, // "-", ".", "/" and digits
+		{'=', '=', 1},
+		{'?', '?', 1},
+		{'A', 'Z', 1},
+		{'^', '~', 1},
+	},
+}
+
+// LocalPart returns a Validator for the local parts (what precedes the "@")
+// of email addresses, in their dot-atom form. Quoted local parts, which
+// RFC 5322 allows but few mail systems accept, are deemed invalid. Local
+// parts are case-sensitive.
+//
+// See https://www.rfc-editor.org/rfc/rfc5322#section-3.4.1 and
+// https://www.rfc-editor.org/rfc/rfc5321#section-4.5.3.1.1
+func LocalPart() usrname.Validator {
+	v, err := custom.New(custom.Options{
+		Name:          "Email local part",
+		CaseSensitive: true,
+		Metadata: usrname.Metadata{
+			Tags:     []string{"email"},
+			RulesURL: "https://www.rfc-editor.org/rfc/rfc5322#section-3.4.1",
+		},
+		Rules: usrname.Rules{
+			MinLength:         1,
+			MaxLength:         64,
+			Whitelist:         atext,
+			IllegalPrefixes:   []string{"."},
+			IllegalSuffixes:   []string{"."},
+			IllegalSubstrings: []string{".."},
+		},
+	})
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return v
+}
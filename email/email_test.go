@@ -0,0 +1,118 @@
+package email_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/email"
+)
+
+var validator = email.LocalPart()
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	if usrname.Equivalent(validator, "FooBar", "foobar") {
+		t.Errorf("Equivalent(%q, %q), got true, want false", "FooBar", "foobar")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"dotatom",
+			"John.Doe",
+			noViolations,
+		}, {
+			"atext",
+			"!#$%&'*+-/=?^_`{|}~",
+			noViolations,
+		}, {
+			"specials",
+			`john"doe@example`,
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{4, 8},
+					Whitelist: validator.Whitelist(),
+				},
+			},
+		}, {
+			"quoted",
+			`"john doe"`,
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{0, 5, 9},
+					Whitelist: validator.Whitelist(),
+				},
+			},
+		}, {
+			"nonascii",
+			"jöhn",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{1},
+					Whitelist: validator.Whitelist(),
+				},
+			},
+		}, {
+			"leadingperiod",
+			".john",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"consecutiveperiods",
+			"john..doe",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: "..",
+					At:      []int{4, 6},
+				},
+			},
+		}, {
+			"trailingperiod",
+			"john.",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"toolong",
+			strings.Repeat("a", 65),
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    64,
+					Actual: 65,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := validator.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
@@ -1,12 +1,27 @@
 package usrname
 
 import (
+	"errors"
 	"fmt"
 )
 
 const (
 	nwErrTempl  = "usrname: network error: %v"
 	uscErrTempl = "usrname: unexpected status code: %d"
+	nfErrTempl  = "usrname: %s not found for %s%s"
+	dupErrTempl = "usrname: %s called twice for %s %s"
+	nilErrTempl = "usrname: %s called with a nil %s for %s"
+	unsErrTempl = "usrname: unsupported rules: %v"
+)
+
+var (
+	// ErrNotFound matches, through errors.Is (Go 1.13 and later), every
+	// *NotFoundError.
+	ErrNotFound = errors.New("usrname: not found")
+	// ErrDuplicate matches, through errors.Is, every *DuplicateError.
+	ErrDuplicate = errors.New("usrname: duplicate registration")
+	// ErrNilChecker matches, through errors.Is, every *NilCheckerError.
+	ErrNilChecker = errors.New("usrname: checker is nil")
 )
 
 type NetworkError struct {
@@ -17,6 +32,16 @@ func (err *NetworkError) Error() string {
 	return fmt.Sprintf(nwErrTempl, err.Cause)
 }
 
+// An UnsupportedRulesError reports that Rules.Regexp cannot express a set of
+// rules, which lie outside the subset it supports.
+type UnsupportedRulesError struct {
+	Cause error
+}
+
+func (err *UnsupportedRulesError) Error() string {
+	return fmt.Sprintf(unsErrTempl, err.Cause)
+}
+
 type UnexpectedStatusCodeError struct {
 	StatusCode int
 }
@@ -24,3 +49,51 @@ type UnexpectedStatusCodeError struct {
 func (err *UnexpectedStatusCodeError) Error() string {
 	return fmt.Sprintf(uscErrTempl, err.StatusCode)
 }
+
+// A NotFoundError reports that nothing is registered under Name, nor under
+// an alias equal to it.
+type NotFoundError struct {
+	Kind        string // "Checker", or "Validator" where checkers also do
+	Name        string
+	Suggestions []string // registered names close to Name, closest first
+}
+
+func (err *NotFoundError) Error() string {
+	return fmt.Sprintf(nfErrTempl, err.Kind, err.Name, didYouMean(err.Suggestions))
+}
+
+func (err *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// A DuplicateError reports that Name, regardless of case, is already
+// registered as a name or as an alias.
+type DuplicateError struct {
+	Op   string // e.g. "Register"
+	Kind string // "checker", "validator" or "alias"
+	Name string
+}
+
+func (err *DuplicateError) Error() string {
+	return fmt.Sprintf(dupErrTempl, err.Op, err.Kind, err.Name)
+}
+
+func (err *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicate
+}
+
+// A NilCheckerError reports that a nil checker or validator was to be
+// registered under Name.
+type NilCheckerError struct {
+	Op   string // e.g. "Register"
+	Kind string // "checker" or "validator"
+	Name string
+}
+
+func (err *NilCheckerError) Error() string {
+	return fmt.Sprintf(nilErrTempl, err.Op, err.Kind, err.Name)
+}
+
+func (err *NilCheckerError) Is(target error) bool {
+	return target == ErrNilChecker
+}
@@ -19,6 +19,7 @@ type facebook struct {
 	whitelist *unicode.RangeTable
 	minLength int
 	maxLength int
+	metadata  usrname.Metadata
 }
 
 var facebookImpl = facebook{
@@ -35,12 +36,20 @@ var facebookImpl = facebook{
 	},
 	minLength: 5,
 	maxLength: 50,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Homepage:  "https://www.facebook.com",
+		Probeable: false,
+	},
 }
 
 func init() {
 	if err := usrname.Register(facebookImpl.name, &facebookImpl); err != nil {
 		panic(err)
 	}
+	if err := usrname.RegisterAlias("fb", facebookImpl.name); err != nil {
+		panic(err)
+	}
 }
 
 func New() usrname.Checker {
@@ -51,6 +60,10 @@ func (s *facebook) Name() string {
 	return s.name
 }
 
+func (s *facebook) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
 func (s *facebook) Link(username string) string {
 	u := url.URL{
 		Scheme: s.scheme,
@@ -68,6 +81,19 @@ func (v *facebook) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *facebook) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Usernames are case-insensitive and periods in them are ignored.
+func (*facebook) Canonicalize(username string) string {
+	return strings.ToLower(strings.Replace(username, ".", "", -1))
+}
+
 // See https://help.facebook.com/en/managing-your-account/facebook-username-rules
 func (v *facebook) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
@@ -135,9 +161,5 @@ func request(username string) *http.Request {
 func checkRedirect(username string, location string) bool {
 	root := facebookImpl.Link("") + "/"
 	ss := strings.SplitAfterN(location, root, 2)
-	return len(ss) == 2 && strings.EqualFold(normalize(ss[1]), normalize(username))
-}
-
-func normalize(s string) string {
-	return strings.Replace(s, ".", "", -1)
+	return len(ss) == 2 && usrname.Equivalent(&facebookImpl, ss[1], username)
 }
@@ -135,6 +135,38 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	expected := &usrname.Rules{
+		MinLength: 5,
+		MaxLength: 50,
+		Whitelist: checker.Whitelist(),
+	}
+	actual := checker.Rules()
+	if !reflect.DeepEqual(actual, expected) {
+		template := "got %#v, want %#v"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		username  string
+		canonical string
+	}{
+		{"foobar", "foobar"},
+		{"Foo.Bar", "foobar"},
+		{"f.o.o.b.a.r", "foobar"},
+	}
+	const template = "Canonicalize(%q), got %q, want %q"
+	for _, c := range cases {
+		if actual := checker.Canonicalize(c.username); actual != c.canonical {
+			t.Errorf(template, c.username, actual, c.canonical)
+		}
+	}
+}
+
 func TestCheck(t *testing.T) {
 	defer leaktest.Check(t)()
 
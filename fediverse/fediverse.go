@@ -0,0 +1,237 @@
+package fediverse
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+// fediverse checks handles of the form "user@instance", where the instance
+// defaults to host.
+type fediverse struct {
+	name            string
+	scheme          string
+	host            string
+	illegalPrefixes []string
+	illegalSuffixes []string
+	reserved        []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+var fediverseImpl = fediverse{
+	name:            "Fediverse",
+	scheme:          "https",
+	host:            "mastodon.social",
+	illegalPrefixes: []string{"-", "."},
+	illegalSuffixes: []string{"-", "."},
+	reserved: []string{
+		"admin", "administrator", "help", "mod", "moderator", "root",
+		"support", "webmaster",
+	},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 1,
+	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"activitypub", "decentralized", "microblogging"},
+		Homepage:  "https://mastodon.social",
+		RulesURL:  "https://github.com/mastodon/mastodon/blob/main/app/models/account.rb",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(fediverseImpl.name, &fediverseImpl); err != nil {
+		panic(err)
+	}
+}
+
+// New returns the Checker for fediverse handles, whose instance defaults to
+// mastodon.social.
+func New() usrname.Checker {
+	return &fediverseImpl
+}
+
+// NewInstance returns a Checker, named name, for fediverse handles whose
+// instance defaults to the one at baseURL (e.g. "https://example.social").
+func NewInstance(name string, baseURL string) (usrname.Checker, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("fediverse: invalid base URL %q", baseURL)
+	}
+	c := fediverseImpl
+	c.name = name
+	c.scheme = u.Scheme
+	c.host = strings.ToLower(u.Host)
+	c.metadata.Homepage = c.scheme + "://" + c.host
+	return &c, nil
+}
+
+// split splits handle, with or without its leading "@", into a username and
+// the host of an instance.
+func (s *fediverse) split(handle string) (username string, host string) {
+	handle = strings.TrimPrefix(handle, "@")
+	if i := strings.Index(handle, "@"); i != -1 {
+		return handle[:i], strings.ToLower(handle[i+1:])
+	}
+	return handle, s.host
+}
+
+// validHost reports whether handles may designate host: the default
+// instance, or a public DNS name (see internal.IsPublicHost).
+func (s *fediverse) validHost(host string) bool {
+	return host == s.host || internal.IsPublicHost(host)
+}
+
+// schemeFor returns the scheme under which host is reached; WebFinger
+// mandates HTTPS, except possibly for the default instance.
+func (s *fediverse) schemeFor(host string) string {
+	if host == s.host {
+		return s.scheme
+	}
+	return "https"
+}
+
+func (s *fediverse) Name() string {
+	return s.name
+}
+
+func (s *fediverse) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *fediverse) Link(handle string) string {
+	username, host := s.split(handle)
+	u := url.URL{
+		Scheme: s.schemeFor(host),
+		Host:   host,
+		Path:   "/@" + username,
+	}
+	return u.String()
+}
+
+func (*fediverse) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *fediverse) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *fediverse) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		IllegalSuffixes: append([]string(nil), v.illegalSuffixes...),
+		Reserved:        append([]string(nil), v.reserved...),
+	}
+}
+
+// Usernames and hostnames are case-insensitive, and handles on the default
+// instance may omit it.
+func (s *fediverse) Canonicalize(handle string) string {
+	username, host := s.split(handle)
+	return strings.ToLower(username) + "@" + host
+}
+
+// Validate, like Rules and therefore Rules().Regexp(), applies to usernames
+// only, not to full handles: Check, which accepts handles, also requires
+// their host part, if any, to be a public DNS name (see validHost).
+//
+// See https://github.com/mastodon/mastodon/blob/main/app/models/account.rb
+func (v *fediverse) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckIllegalSuffixes(v.illegalSuffixes),
+		internal.CheckNotReserved(v.reserved),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *fediverse) Check(client usrname.Client) func(string) usrname.Result {
+	return func(handle string) (r usrname.Result) {
+		r.Username = handle
+		r.Checker = c
+
+		username, host := c.split(handle)
+		if vv := c.Validate(username); len(vv) != 0 || !c.validHost(host) {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, handle, c.Name())
+			return
+		}
+
+		req := c.request(username, host)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", host)
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			var jrd struct {
+				Subject string `json:"subject"`
+			}
+			if err := internal.DecodeJSON(res, &jrd); err != nil || jrd.Subject == "" {
+				r.Status = usrname.UnknownStatus
+				r.Message = "unexpected response body"
+				return
+			}
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+// request resolves the account through WebFinger, which every instance
+// serves, whatever software it runs.
+func (c *fediverse) request(username string, host string) *http.Request {
+	u := url.URL{
+		Scheme:   c.schemeFor(host),
+		Host:     host,
+		Path:     "/.well-known/webfinger",
+		RawQuery: url.Values{"resource": {"acct:" + username + "@" + host}}.Encode(),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	req.Header.Add("Accept", "application/jrd+json")
+	return req
+}
@@ -0,0 +1,335 @@
+package fediverse_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/fediverse"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = fediverse.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Fediverse"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		handle string
+		link   string
+	}{
+		{"foobar", "https://mastodon.social/@foobar"},
+		{"@foobar", "https://mastodon.social/@foobar"},
+		{"foobar@Example.Social", "https://example.social/@foobar"},
+		{"@foobar@example.social", "https://example.social/@foobar"},
+	}
+	const template = "Link(%q), got %q, want %q"
+	for _, c := range cases {
+		if actual := checker.Link(c.handle); actual != c.link {
+			t.Errorf(template, c.handle, actual, c.link)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	const template = "Equivalent(%q, %q), got %t, want %t"
+	for _, pair := range [][2]string{
+		{"FooBar", "@foobar@mastodon.social"},
+		{"foobar@Example.Social", "@FOOBAR@example.social"},
+	} {
+		if !usrname.Equivalent(checker, pair[0], pair[1]) {
+			t.Errorf(template, pair[0], pair[1], false, true)
+		}
+	}
+	if usrname.Equivalent(checker, "foobar", "foobar@example.social") {
+		t.Errorf(template, "foobar", "foobar@example.social", true, false)
+	}
+}
+
+func TestNewInstance(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := fediverse.NewInstance("ACME", "https://Example.Social")
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	const template = "got %q, want %q"
+	if actual, expected := c.Name(), "ACME"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := c.Link("foobar"), "https://example.social/@foobar"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if _, err := fediverse.NewInstance("relative", "example.social"); err == nil {
+		t.Errorf("NewInstance with relative URL, got no error, want one")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"specialchars",
+			"Foo.bar-baz_",
+			noViolations,
+		}, {
+			"exoticchars",
+			"exotic^chars",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{6},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"periodprefix",
+			".bar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"hyphensuffix",
+			"bar-",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: "-",
+				},
+			},
+		}, {
+			"reserved",
+			"Admin",
+			[]usrname.Violation{
+				&usrname.Reserved{
+					Word: "admin",
+				},
+			},
+		}, {
+			"toolong",
+			"0123456789012345678901234567890",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    30,
+					Actual: 31,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+// webfinger resolves account "taken" through WebFinger; it responds to
+// "garbled" with a corrupt JRD and to "broken" with a server error.
+func webfinger(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/.well-known/webfinger" {
+		http.NotFound(w, r)
+		return
+	}
+	resource := r.URL.Query().Get("resource")
+	switch resource {
+	case "acct:taken@" + r.Host:
+		w.Header().Set("Content-Type", "application/jrd+json")
+		fmt.Fprintf(w, `{"subject":%q,"links":[]}`, resource)
+	case "acct:garbled@" + r.Host:
+		fmt.Fprint(w, `<html>`)
+	case "acct:broken@" + r.Host:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	ts := httptest.NewServer(http.HandlerFunc(webfinger))
+	defer ts.Close()
+	instance, err := fediverse.NewInstance("test", ts.URL)
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	// other instances are reached over HTTPS, under public names only; the
+	// test server's certificate is valid for example.com
+	tls := httptest.NewTLSServer(http.HandlerFunc(webfinger))
+	defer tls.Close()
+	client := tls.Client()
+	client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, tls.Listener.Addr().String())
+	}
+	const other = "@example.com"
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "invalidhost",
+			username: "foobar@example.social/path",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "loopback",
+			username: "foobar@127.0.0.1:8080",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "linklocal",
+			username: "foobar@169.254.169.254",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "private",
+			username: "foobar@10.0.0.1",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "ipv6",
+			username: "foobar@[::1]",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "singlelabel",
+			username: "foobar@localhost",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "privatetld",
+			username: "foobar@printer.local",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "port",
+			username: "foobar@example.social:8443",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "notfound",
+			username: "free",
+			client:   usrname.NewClient(),
+			status:   usrname.Available,
+		}, {
+			label:    "jrd",
+			username: "@taken",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "garbled",
+			username: "garbled",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than 200, 404
+			username: "broken",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "otherinstancenotfound",
+			username: "free" + other,
+			client:   bufferingClient{client},
+			status:   usrname.Available,
+		}, {
+			label:    "otherinstancejrd",
+			username: "@taken" + other,
+			client:   bufferingClient{client},
+			status:   usrname.Unavailable,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := instance.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+// bufferingClient closes response bodies, as usrname.Client implementations
+// must.
+type bufferingClient struct {
+	*http.Client
+}
+
+func (c bufferingClient) Do(req *http.Request) (*http.Response, error) {
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return res, nil
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
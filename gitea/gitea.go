@@ -0,0 +1,219 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type gitea struct {
+	name            string
+	scheme          string
+	host            string
+	path            string // for instances served under a relative URL
+	illegalPrefixes []string
+	illegalSuffixes []string
+	illegalPattern  *regexp.Regexp
+	reserved        []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+// Codeberg runs Forgejo, a fork of Gitea that shares its username rules and
+// its API.
+var codebergImpl = gitea{
+	name:            "Codeberg",
+	scheme:          "https",
+	host:            "codeberg.org",
+	illegalPrefixes: []string{"-", ".", "_"},
+	illegalSuffixes: []string{"-", ".", "_", ".atom", ".gpg", ".keys", ".png", ".rss"},
+	illegalPattern:  regexp.MustCompile(`[-._]{2}`),
+	reserved: []string{
+		".", "..", ".well-known", "admin", "api", "assets", "attachments",
+		"avatar", "avatars", "captcha", "commits", "debug", "error", "explore",
+		"favicon.ico", "ghost", "issues", "login", "manifest.json", "metrics",
+		"milestones", "new", "notifications", "org", "pulls", "raw", "repo",
+		"repo-avatars", "robots.txt", "search", "serviceworker.js", "ssh_info",
+		"swagger.v1.json", "user", "v2",
+	},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 1,
+	maxLength: 40,
+	metadata: usrname.Metadata{
+		Category:  usrname.Code,
+		Tags:      []string{"code-hosting", "git"},
+		Homepage:  "https://codeberg.org",
+		RulesURL:  "https://github.com/go-gitea/gitea/blob/main/modules/validation/helpers.go",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(codebergImpl.name, &codebergImpl); err != nil {
+		panic(err)
+	}
+}
+
+// New returns the Checker for Codeberg.
+func New() usrname.Checker {
+	return &codebergImpl
+}
+
+// NewInstance returns a Checker, named name, for the Gitea or Forgejo
+// instance at baseURL (e.g. "https://gitea.example.com").
+func NewInstance(name string, baseURL string) (usrname.Checker, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("gitea: invalid base URL %q", baseURL)
+	}
+	c := codebergImpl
+	c.name = name
+	c.scheme = u.Scheme
+	c.host = u.Host
+	c.path = strings.TrimSuffix(u.Path, "/")
+	c.metadata.Homepage = c.scheme + "://" + c.host + c.path
+	return &c, nil
+}
+
+// Register registers, under name, a Checker for the instance at baseURL and
+// returns it. Unlike the registration of Codeberg, it reports failures (e.g.
+// a name already taken) instead of panicking, so that instances can be
+// registered side by side at any time.
+func Register(name string, baseURL string) (usrname.Checker, error) {
+	c, err := NewInstance(name, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := usrname.Register(name, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *gitea) Name() string {
+	return s.name
+}
+
+func (s *gitea) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *gitea) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   s.path + "/" + username,
+	}
+	return u.String()
+}
+
+func (v *gitea) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *gitea) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *gitea) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		IllegalSuffixes: append([]string(nil), v.illegalSuffixes...),
+		IllegalPattern:  v.illegalPattern.String(),
+		Reserved:        append([]string(nil), v.reserved...),
+	}
+}
+
+// Usernames are case-insensitive.
+func (*gitea) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://github.com/go-gitea/gitea/blob/main/modules/validation/helpers.go
+// and https://github.com/go-gitea/gitea/blob/main/models/user/user.go
+func (v *gitea) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckIllegalSuffixes(v.illegalSuffixes),
+		internal.CheckNotReserved(v.reserved),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *gitea) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+// request looks username up through the users API, which covers
+// organizations as well as users.
+func (c *gitea) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   c.path + "/api/v1/users/" + username,
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
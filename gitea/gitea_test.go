@@ -0,0 +1,249 @@
+package gitea_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/gitea"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = gitea.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Codeberg"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://codeberg.org/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestNewInstance(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := gitea.NewInstance("ACME Forgejo", "https://example.com/forgejo/")
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	const template = "got %q, want %q"
+	if actual, expected := c.Name(), "ACME Forgejo"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := c.Link("foobar"), "https://example.com/forgejo/foobar"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if _, err := gitea.NewInstance("relative", "example.com"); err == nil {
+		t.Errorf("NewInstance with relative URL, got no error, want one")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer leaktest.Check(t)()
+	for _, name := range []string{"Gitea A", "Gitea B"} {
+		c, err := gitea.Register(name, "https://"+name[len(name)-1:]+".example.com")
+		if err != nil {
+			t.Fatalf("Register(%q), unexpected error %v", name, err)
+		}
+		if actual, _ := usrname.CheckerFor(name); actual != c {
+			t.Errorf("CheckerFor(%q), got %v, want %v", name, actual, c)
+		}
+	}
+	if _, err := gitea.Register("Gitea A", "https://c.example.com"); err == nil {
+		t.Errorf("Register twice, got no error, want one")
+	}
+	if _, err := gitea.Register("Codeberg", "https://codeberg.org"); err == nil {
+		t.Errorf("Register(%q), got no error, want one", "Codeberg")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"onechar",
+			"a",
+			noViolations,
+		}, {
+			"specialchars",
+			"foo.bar-baz_qux",
+			noViolations,
+		}, {
+			"exoticchars",
+			"exotic^chars",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{6},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"underscoreprefix",
+			"_bar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: "_",
+				},
+			},
+		}, {
+			"consecutive",
+			"foo.-bar",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{3, 5},
+				},
+			},
+		}, {
+			"hyphensuffix",
+			"bar-",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: "-",
+				},
+			},
+		}, {
+			"keyssuffix",
+			"bar.keys",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".keys",
+				},
+			},
+		}, {
+			"reserved",
+			"Explore",
+			[]usrname.Violation{
+				&usrname.Reserved{
+					Word: "explore",
+				},
+			},
+		}, {
+			"toolong",
+			"01234567890123456789012345678901234567890",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    40,
+					Actual: 41,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/users/taken":
+			w.Write([]byte(`{"id":1,"login":"taken"}`))
+		case "/api/v1/users/free":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+	instance, err := gitea.NewInstance("test", ts.URL)
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "notfound",
+			username: "free",
+			client:   usrname.NewClient(),
+			status:   usrname.Available,
+		}, {
+			label:    "ok",
+			username: "taken",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "other", // than 200, 404
+			username: "broken",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := instance.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/jubobs/usrname"
@@ -21,6 +22,7 @@ type github struct {
 	whitelist        *unicode.RangeTable
 	minLength        int
 	maxLength        int
+	metadata         usrname.Metadata
 }
 
 var githubImpl = github{
@@ -40,12 +42,21 @@ var githubImpl = github{
 	},
 	minLength: 1,
 	maxLength: 39,
+	metadata: usrname.Metadata{
+		Category:  usrname.Code,
+		Tags:      []string{"code-hosting", "git"},
+		Homepage:  "https://github.com",
+		Probeable: true,
+	},
 }
 
 func init() {
 	if err := usrname.Register(githubImpl.name, &githubImpl); err != nil {
 		panic(err)
 	}
+	if err := usrname.RegisterAlias("gh", githubImpl.name); err != nil {
+		panic(err)
+	}
 }
 
 func New() usrname.Checker {
@@ -56,6 +67,10 @@ func (s *github) Name() string {
 	return s.name
 }
 
+func (s *github) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
 func (*github) Link(username string) string {
 	u := url.URL{
 		Scheme: githubImpl.scheme,
@@ -73,6 +88,22 @@ func (v *github) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *github) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:         v.minLength,
+		MaxLength:         v.maxLength,
+		Whitelist:         v.whitelist,
+		IllegalPrefixes:   []string{v.illegalPrefix},
+		IllegalSuffixes:   []string{v.illegalSuffix},
+		IllegalSubstrings: []string{v.illegalSubstring},
+	}
+}
+
+// Usernames are case-insensitive.
+func (*github) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
 // See https://help.github.com/en/managing-your-account/github-username-rules
 func (v *github) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
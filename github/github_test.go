@@ -83,6 +83,7 @@ func TestValidate(t *testing.T) {
 			[]usrname.Violation{
 				&usrname.IllegalSubstring{
 					Pattern: "--",
+					At:      []int{3, 5},
 				},
 			},
 		}, {
@@ -127,6 +128,23 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	expected := &usrname.Rules{
+		MinLength:         1,
+		MaxLength:         39,
+		Whitelist:         checker.Whitelist(),
+		IllegalPrefixes:   []string{"-"},
+		IllegalSuffixes:   []string{"-"},
+		IllegalSubstrings: []string{"--"},
+	}
+	actual := checker.Rules()
+	if !reflect.DeepEqual(actual, expected) {
+		template := "got %#v, want %#v"
+		t.Errorf(template, actual, expected)
+	}
+}
+
 func TestCheck(t *testing.T) {
 	defer leaktest.Check(t)()
 
@@ -182,6 +200,24 @@ func TestCheck(t *testing.T) {
 	}
 }
 
+func TestCheckRequest(t *testing.T) {
+	defer leaktest.Check(t)()
+	client := mockclient.NewScript().
+		On("HEAD", "https://github.com/foo", mockclient.Response{StatusCode: http.StatusOK})
+	if res := checker.Check(client)("foo"); res.Status != usrname.Unavailable {
+		t.Errorf("Check(%q), got %q, want %q", "foo", res.Status, usrname.Unavailable)
+	}
+	reqs := client.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	const template = "got request %s %s, want %s %s"
+	const method, url = "HEAD", "https://github.com/foo"
+	if reqs[0].Method != method || reqs[0].URL.String() != url {
+		t.Errorf(template, reqs[0].Method, reqs[0].URL, method, url)
+	}
+}
+
 type timeoutError struct {
 	error
 }
@@ -0,0 +1,301 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type gitlab struct {
+	name            string
+	scheme          string
+	host            string
+	path            string // for instances served under a relative URL
+	token           string // personal access token, if any
+	illegalPrefix   string
+	illegalSuffixes []string
+	reserved        []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+var gitlabImpl = gitlab{
+	name:            "GitLab",
+	scheme:          "https",
+	host:            "gitlab.com",
+	illegalPrefix:   "-",
+	illegalSuffixes: []string{".", ".git", ".atom"},
+	reserved: []string{
+		".well-known", "404.html", "422.html", "500.html", "502.html",
+		"503.html", "admin", "api", "apple-touch-icon.png", "assets",
+		"dashboard", "deploy.html", "explore", "favicon.ico", "favicon.png",
+		"files", "groups", "health_check", "help", "import", "jwt", "login",
+		"oauth", "profile", "projects", "public", "robots.txt", "s", "search",
+		"sitemap", "sitemap.xml", "sitemap.xml.gz", "slash-command-logo.png",
+		"snippets", "unsubscribes", "uploads", "users", "v2",
+	},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 2,
+	maxLength: 255,
+	metadata: usrname.Metadata{
+		Category:  usrname.Code,
+		Tags:      []string{"code-hosting", "git"},
+		Homepage:  "https://gitlab.com",
+		RulesURL:  "https://docs.gitlab.com/ee/user/reserved_names.html",
+		Probeable: false, // private groups are invisible without a token
+	},
+}
+
+func init() {
+	if err := usrname.Register(gitlabImpl.name, &gitlabImpl); err != nil {
+		panic(err)
+	}
+	if err := usrname.RegisterAlias("gl", gitlabImpl.name); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &gitlabImpl
+}
+
+// NewInstance returns a Checker, named name, for the self-managed GitLab
+// instance at baseURL (e.g. "https://gitlab.example.com").
+func NewInstance(name string, baseURL string) (usrname.Checker, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("gitlab: invalid base URL %q", baseURL)
+	}
+	c := gitlabImpl
+	c.name = name
+	c.scheme = u.Scheme
+	c.host = u.Host
+	c.path = strings.TrimSuffix(u.Path, "/")
+	c.metadata.Homepage = c.scheme + "://" + c.host + c.path
+	return &c, nil
+}
+
+// NewInstanceWithToken is like NewInstance, but the Checker authenticates
+// with the personal access token token, which lets it see private groups
+// and therefore report names as available without caveat.
+func NewInstanceWithToken(name string, baseURL string, token string) (usrname.Checker, error) {
+	c, err := NewInstance(name, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	g := c.(*gitlab)
+	g.token = token
+	g.metadata.Probeable = true
+	return g, nil
+}
+
+// Register registers, under name, a Checker for the instance at baseURL and
+// returns it.
+func Register(name string, baseURL string) (usrname.Checker, error) {
+	c, err := NewInstance(name, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := usrname.Register(name, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *gitlab) Name() string {
+	return s.name
+}
+
+func (s *gitlab) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *gitlab) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   s.path + "/" + username,
+	}
+	return u.String()
+}
+
+func (*gitlab) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *gitlab) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *gitlab) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: []string{v.illegalPrefix},
+		IllegalSuffixes: append([]string(nil), v.illegalSuffixes...),
+		Reserved:        append([]string(nil), v.reserved...),
+	}
+}
+
+// Usernames are case-insensitive.
+func (*gitlab) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://docs.gitlab.com/ee/user/reserved_names.html
+func (v *gitlab) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefix(v.illegalPrefix),
+		internal.CheckIllegalSuffixes(v.illegalSuffixes),
+		internal.CheckNotReserved(v.reserved),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *gitlab) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		// Users and groups share a namespace; a username held by a group is
+		// not available either.
+		steps := []func(usrname.Client, string) (usrname.Status, string, error){
+			c.checkUsers,
+			c.checkGroups,
+		}
+		if c.token != "" {
+			steps[1] = c.checkNamespaces
+		}
+		for _, step := range steps {
+			status, msg, err := step(client, username)
+			if err != nil {
+				r.Status = usrname.UnknownStatus
+				if internal.IsTimeout(err) {
+					r.Message = fmt.Sprintf("%s timed out", c.Name())
+				} else {
+					r.Message = "Something went wrong"
+				}
+				return
+			}
+			if status != "" {
+				r.Status = status
+				r.Message = msg
+				return
+			}
+		}
+		return
+	}
+}
+
+// checkUsers looks username up through the users API, which, unlike profile
+// pages, answers in JSON and does not require authentication. It reports no
+// status if no user has username.
+func (c *gitlab) checkUsers(client usrname.Client, username string) (usrname.Status, string, error) {
+	res, err := client.Do(c.request("/api/v4/users", url.Values{"username": {username}}))
+	if err != nil {
+		return "", "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return usrname.UnknownStatus, fmt.Sprintf("unsupported status code %d", res.StatusCode), nil
+	}
+	var users []struct {
+		Username string `json:"username"`
+	}
+	if err := internal.DecodeJSON(res, &users); err != nil {
+		return usrname.UnknownStatus, "unexpected response body", nil
+	}
+	if len(users) != 0 {
+		return usrname.Unavailable, "", nil
+	}
+	return "", "", nil
+}
+
+// checkGroups looks username up through the groups API. Private groups are
+// invisible without authentication, so a name that no user or public group
+// holds is reported as available, with a caveat, though it may still be
+// taken.
+func (c *gitlab) checkGroups(client usrname.Client, username string) (usrname.Status, string, error) {
+	res, err := client.Do(c.request("/api/v4/groups/"+username, nil))
+	if err != nil {
+		return "", "", err
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return usrname.Unavailable, "held by a group", nil
+	case http.StatusNotFound:
+		const msg = "no user or public group holds it, but a private group may"
+		return usrname.Available, msg, nil
+	default:
+		return usrname.UnknownStatus, fmt.Sprintf("unsupported status code %d", res.StatusCode), nil
+	}
+}
+
+// checkNamespaces asks whether any user or group, private or not, holds
+// username; the namespaces API requires authentication.
+func (c *gitlab) checkNamespaces(client usrname.Client, username string) (usrname.Status, string, error) {
+	path := "/api/v4/namespaces/" + username + "/exists"
+	res, err := client.Do(c.request(path, nil))
+	if err != nil {
+		return "", "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return usrname.UnknownStatus, fmt.Sprintf("unsupported status code %d", res.StatusCode), nil
+	}
+	var body struct {
+		Exists *bool `json:"exists"`
+	}
+	if err := internal.DecodeJSON(res, &body); err != nil || body.Exists == nil {
+		return usrname.UnknownStatus, "unexpected response body", nil
+	}
+	if *body.Exists {
+		return usrname.Unavailable, "held by a group", nil
+	}
+	return usrname.Available, "", nil
+}
+
+func (c *gitlab) request(path string, query url.Values) *http.Request {
+	u := url.URL{
+		Scheme:   c.scheme,
+		Host:     c.host,
+		Path:     c.path + path,
+		RawQuery: query.Encode(),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+	return req
+}
@@ -0,0 +1,325 @@
+package gitlab_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/gitlab"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = gitlab.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "GitLab"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://gitlab.com/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestNewInstance(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := gitlab.NewInstance("ACME GitLab", "https://example.com/gitlab/")
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	const template = "got %q, want %q"
+	if actual, expected := c.Name(), "ACME GitLab"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := c.Link("foobar"), "https://example.com/gitlab/foobar"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if _, err := gitlab.NewInstance("relative", "example.com"); err == nil {
+		t.Errorf("NewInstance with relative URL, got no error, want one")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"onechar",
+			"a",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    2,
+					Actual: 1,
+				},
+			},
+		}, {
+			"specialchars",
+			"foo.bar-baz_qux",
+			noViolations,
+		}, {
+			"exoticchars",
+			"exotic^chars",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{6},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"hyphenprefix",
+			"-bar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: "-",
+				},
+			},
+		}, {
+			"periodsuffix",
+			"bar.",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"gitsuffix",
+			"bar.git",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".git",
+				},
+			},
+		}, {
+			"atomsuffix",
+			"bar.atom",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".atom",
+				},
+			},
+		}, {
+			"reserved",
+			"Admin",
+			[]usrname.Violation{
+				&usrname.Reserved{
+					Word: "admin",
+				},
+			},
+		}, {
+			"toolong",
+			string(make([]byte, 256)),
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        seq(256),
+					Whitelist: checker.Whitelist(),
+				},
+				&usrname.TooLong{
+					Max:    255,
+					Actual: 256,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	rules := checker.Rules()
+	if rules.MinLength != 2 || rules.MaxLength != 255 {
+		template := "got lengths %d-%d, want 2-255"
+		t.Errorf(template, rules.MinLength, rules.MaxLength)
+	}
+	expected := []string{".", ".git", ".atom"}
+	if !reflect.DeepEqual(rules.IllegalSuffixes, expected) {
+		template := "got %q, want %q"
+		t.Errorf(template, rules.IllegalSuffixes, expected)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated := r.Header.Get("PRIVATE-TOKEN") == "secret"
+		switch r.URL.Path {
+		case "/api/v4/users":
+			switch r.URL.Query().Get("username") {
+			case "taken":
+				fmt.Fprint(w, `[{"id":1,"username":"taken","state":"active"}]`)
+			case "garbled":
+				fmt.Fprint(w, `<html>`)
+			case "broken":
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				fmt.Fprint(w, `[]`)
+			}
+		case "/api/v4/groups/group", "/api/v4/groups/private":
+			if r.URL.Path == "/api/v4/groups/private" && !authenticated {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, `{"id":2,"path":"group"}`)
+		case "/api/v4/namespaces/private/exists", "/api/v4/namespaces/group/exists":
+			if !authenticated {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"exists":true}`)
+		case "/api/v4/namespaces/free/exists":
+			if !authenticated {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"exists":false}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	instance, err := gitlab.NewInstance("test", ts.URL)
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	authenticated, err := gitlab.NewInstanceWithToken("test", ts.URL, "secret")
+	if err != nil {
+		t.Fatalf("NewInstanceWithToken, unexpected error %v", err)
+	}
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "empty", // but private groups are invisible
+			username: "free",
+			client:   usrname.NewClient(),
+			status:   usrname.Available,
+		}, {
+			label:    "group",
+			username: "group",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "nonempty",
+			username: "taken",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "garbled",
+			username: "garbled",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than 200
+			username: "broken",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := instance.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+
+	if res := instance.Check(usrname.NewClient())("free"); res.Message == "" {
+		t.Errorf("Check(%q), got no caveat about private groups", "free")
+	}
+
+	authenticatedCases := []struct {
+		username string
+		status   usrname.Status
+	}{
+		{"free", usrname.Available},
+		{"private", usrname.Unavailable},
+		{"taken", usrname.Unavailable},
+	}
+	for _, c := range authenticatedCases {
+		res := authenticated.Check(usrname.NewClient())(c.username)
+		if res.Status != c.status {
+			t.Errorf("with token, "+template, c.username, res.Status, c.status)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := gitlab.Register("GitLab test", "https://gitlab.example.com")
+	if err != nil {
+		t.Fatalf("Register, unexpected error %v", err)
+	}
+	if actual, _ := usrname.CheckerFor("GitLab test"); actual != c {
+		t.Errorf("CheckerFor, got %v, want %v", actual, c)
+	}
+	if _, err := gitlab.Register("GitLab", "https://gitlab.example.com"); err == nil {
+		t.Errorf("Register(%q), got no error, want one", "GitLab")
+	}
+}
+
+func seq(n int) []int {
+	ii := make([]int, n)
+	for i := range ii {
+		ii[i] = i
+	}
+	return ii
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
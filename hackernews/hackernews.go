@@ -0,0 +1,168 @@
+package hackernews
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type hackernews struct {
+	name      string
+	scheme    string
+	host      string
+	notFound  string // the whole body of the pages of nonexistent users
+	found     string // marks the pages of existing users
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var hackernewsImpl = hackernews{
+	name:     "Hacker News",
+	scheme:   "https",
+	host:     "news.ycombinator.com",
+	notFound: "No such user.",
+	found:    `>user:</td>`,
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 2,
+	maxLength: 15,
+	metadata: usrname.Metadata{
+		Category:  usrname.Code,
+		Tags:      []string{"community", "news"},
+		Homepage:  "https://news.ycombinator.com",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(hackernewsImpl.name, &hackernewsImpl); err != nil {
+		panic(err)
+	}
+	if err := usrname.RegisterAlias("hn", hackernewsImpl.name); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &hackernewsImpl
+}
+
+func (s *hackernews) Name() string {
+	return s.name
+}
+
+func (s *hackernews) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *hackernews) Link(username string) string {
+	u := url.URL{
+		Scheme:   s.scheme,
+		Host:     s.host,
+		Path:     "/user",
+		RawQuery: url.Values{"id": {username}}.Encode(),
+	}
+	return u.String()
+}
+
+func (*hackernews) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *hackernews) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *hackernews) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Usernames are case-insensitive.
+func (*hackernews) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See the error message of https://news.ycombinator.com/login
+func (v *hackernews) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *hackernews) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		if res.StatusCode != http.StatusOK {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			return
+		}
+		// Profile pages are served, with status code 200, whether the user
+		// exists or not. Their markers come from hand-written fixtures, not
+		// from Hacker News, so they only make the message more specific.
+		body, err := internal.ReadBody(res)
+		r.Status = usrname.UnknownStatus
+		switch {
+		case err != nil:
+			r.Message = "unexpected response body"
+		case string(bytes.TrimSpace(body)) == c.notFound:
+			r.Message = "page of a nonexistent user, going by unverified markup"
+		case bytes.Contains(body, []byte(c.found)):
+			r.Message = "profile page, going by unverified markup"
+		default:
+			r.Message = "unexpected response body"
+		}
+		return
+	}
+}
+
+func (c *hackernews) request(username string) *http.Request {
+	req, err := http.NewRequest("GET", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
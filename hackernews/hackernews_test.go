@@ -0,0 +1,161 @@
+package hackernews_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/hackernews"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = hackernews.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Hacker News"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://news.ycombinator.com/user?id=" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"a",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    2,
+					Actual: 1,
+				},
+			},
+		}, {
+			"specialchars",
+			"Foo-bar_42",
+			noViolations,
+		}, {
+			"period",
+			"foo.bar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"toolong",
+			"0123456789abcdef",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    15,
+					Actual: 16,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+		message  string // if not empty
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "user",
+			username: "pg",
+			client:   mockclient.WithResponseFile("testdata/user.http"),
+			status:   usrname.UnknownStatus,
+			message:  "profile page, going by unverified markup",
+		}, {
+			label:    "nouser",
+			username: "dummy",
+			client:   mockclient.WithResponseFile("testdata/nouser.http"),
+			status:   usrname.UnknownStatus,
+			message:  "page of a nonexistent user, going by unverified markup",
+		}, {
+			label:    "ratelimited",
+			username: "dummy",
+			client:   mockclient.WithResponseFile("testdata/ratelimited.http"),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than 200
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusServiceUnavailable),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+			if c.message != "" && res.Message != c.message {
+				t.Errorf("Check(%q), got message %q, want %q", c.username, res.Message, c.message)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
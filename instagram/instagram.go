@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/jubobs/usrname"
@@ -21,6 +22,7 @@ type instagram struct {
 	whitelist        *unicode.RangeTable
 	minLength        int
 	maxLength        int
+	metadata         usrname.Metadata
 }
 
 var instagramImpl = instagram{
@@ -41,12 +43,21 @@ var instagramImpl = instagram{
 	},
 	minLength: 1,
 	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"photos"},
+		Homepage:  "https://www.instagram.com",
+		Probeable: false,
+	},
 }
 
 func init() {
 	if err := usrname.Register(instagramImpl.name, &instagramImpl); err != nil {
 		panic(err)
 	}
+	if err := usrname.RegisterAlias("ig", instagramImpl.name); err != nil {
+		panic(err)
+	}
 }
 
 func New() usrname.Checker {
@@ -57,6 +68,10 @@ func (s *instagram) Name() string {
 	return s.name
 }
 
+func (s *instagram) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
 func (*instagram) Link(username string) string {
 	u := url.URL{
 		Scheme: instagramImpl.scheme,
@@ -74,6 +89,22 @@ func (v *instagram) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *instagram) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:         v.minLength,
+		MaxLength:         v.maxLength,
+		Whitelist:         v.whitelist,
+		IllegalPrefixes:   []string{v.illegalPrefix},
+		IllegalSuffixes:   []string{v.illegalSuffix},
+		IllegalSubstrings: []string{v.illegalSubstring},
+	}
+}
+
+// Usernames are case-insensitive.
+func (*instagram) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
 // See https://help.instagram.com/en/managing-your-account/instagram-username-rules
 func (v *instagram) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
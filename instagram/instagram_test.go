@@ -83,6 +83,7 @@ func TestValidate(t *testing.T) {
 			[]usrname.Violation{
 				&usrname.IllegalSubstring{
 					Pattern: "..",
+					At:      []int{3, 5},
 				},
 			},
 		}, {
@@ -127,6 +128,23 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	expected := &usrname.Rules{
+		MinLength:         1,
+		MaxLength:         30,
+		Whitelist:         checker.Whitelist(),
+		IllegalPrefixes:   []string{"."},
+		IllegalSuffixes:   []string{"."},
+		IllegalSubstrings: []string{".."},
+	}
+	actual := checker.Rules()
+	if !reflect.DeepEqual(actual, expected) {
+		template := "got %#v, want %#v"
+		t.Errorf(template, actual, expected)
+	}
+}
+
 func TestCheck(t *testing.T) {
 	defer leaktest.Check(t)()
 
@@ -0,0 +1,463 @@
+package dfa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"unicode"
+)
+
+// A node is a regular expression under construction; the nil node matches
+// nothing. Nodes share their subexpressions, so size, the number of nodes of
+// the expression once written out, may be far larger than the node itself.
+type node struct {
+	op       syntax.Op
+	set      []rune
+	subs     []*node
+	min, max int
+	size     int
+	id       string
+}
+
+var empty = &node{op: syntax.OpEmptyMatch, size: 1}
+
+// maxSize bounds the size of the expressions that the converter writes out,
+// and maxWork the number of ways it tries to split paths in doing so.
+const (
+	maxSize = 1 << 16
+	maxWork = 1 << 20
+)
+
+// sizeOf returns the size of a node made of nn, saturated past maxSize.
+func sizeOf(nn []*node) int {
+	size := 1
+	for _, n := range nn {
+		if size += n.size; size > maxSize {
+			return maxSize + 1
+		}
+	}
+	return size
+}
+
+func class(set []rune) *node {
+	if len(set) == 0 {
+		return nil
+	}
+	return &node{op: syntax.OpCharClass, set: set, size: 1}
+}
+
+func repeat(n *node, min, max int) *node {
+	switch {
+	case n == nil && min == 0:
+		return empty
+	case n == nil:
+		return nil
+	case n == empty || max == 0:
+		return empty
+	case min == 1 && max == 1:
+		return n
+	}
+	return &node{
+		op:   syntax.OpRepeat,
+		subs: []*node{n},
+		min:  min,
+		max:  max,
+		size: sizeOf([]*node{n}),
+	}
+}
+
+func concat(nn ...*node) *node {
+	c := node{op: syntax.OpConcat}
+	for _, n := range nn {
+		switch {
+		case n == nil:
+			return nil
+		case n == empty:
+		case n.op == syntax.OpConcat:
+			c.subs = append(c.subs, n.subs...)
+		default:
+			c.subs = append(c.subs, n)
+		}
+	}
+	switch len(c.subs) {
+	case 0:
+		return empty
+	case 1:
+		return c.subs[0]
+	}
+	c.size = sizeOf(c.subs)
+	return &c
+}
+
+func alternate(nn ...*node) *node {
+	a := node{op: syntax.OpAlternate}
+	optional := false
+	seen := map[string]bool{}
+	for _, n := range nn {
+		if n == nil {
+			continue
+		}
+		subs := []*node{n}
+		switch n.op {
+		case syntax.OpAlternate:
+			subs = n.subs
+		case syntax.OpQuest:
+			subs = n.subs
+			if subs[0].op == syntax.OpAlternate {
+				subs = subs[0].subs
+			}
+			optional = true
+		}
+		for _, sub := range subs {
+			if sub == empty {
+				optional = true
+			} else if k := sub.key(); !seen[k] {
+				seen[k] = true
+				a.subs = append(a.subs, sub)
+			}
+		}
+	}
+	var n *node
+	switch len(a.subs) {
+	case 0:
+	case 1:
+		n = a.subs[0]
+	default:
+		a.size = sizeOf(a.subs)
+		n = &a
+	}
+	if optional {
+		if n == nil {
+			return empty
+		}
+		return &node{op: syntax.OpQuest, subs: []*node{n}, size: sizeOf([]*node{n})}
+	}
+	return n
+}
+
+// key returns a digest of the structure of n, so that structurally equal
+// nodes have equal keys. Unlike writing n out, it takes time independent of
+// its size.
+func (n *node) key() string {
+	if n.id == "" {
+		h := sha256.New()
+		fmt.Fprintf(h, "%d %v %d %d", n.op, n.set, n.min, n.max)
+		for _, sub := range n.subs {
+			h.Write([]byte(sub.key()))
+		}
+		n.id = string(h.Sum(nil))
+	}
+	return n.id
+}
+
+// String writes n out in RE2 syntax. Unlike syntax.Regexp's String method,
+// which looks at every rune of character classes, it writes classes range by
+// range, which matters for large whitelists.
+func (n *node) String() string {
+	var b bytes.Buffer
+	n.write(&b)
+	return b.String()
+}
+
+func (n *node) write(b *bytes.Buffer) {
+	if n == nil {
+		b.WriteString(`[^\x00-\x{10FFFF}]`)
+		return
+	}
+	switch n.op {
+	case syntax.OpEmptyMatch:
+		b.WriteString(`(?:)`)
+	case syntax.OpCharClass:
+		writeClass(b, n.set)
+	case syntax.OpConcat:
+		for _, sub := range n.subs {
+			if sub.op == syntax.OpAlternate {
+				b.WriteString(`(?:`)
+				sub.write(b)
+				b.WriteString(`)`)
+			} else {
+				sub.write(b)
+			}
+		}
+	case syntax.OpAlternate:
+		for i, sub := range n.subs {
+			if i != 0 {
+				b.WriteByte('|')
+			}
+			sub.write(b)
+		}
+	case syntax.OpQuest, syntax.OpRepeat:
+		sub := n.subs[0]
+		if sub.op == syntax.OpCharClass {
+			sub.write(b)
+		} else {
+			b.WriteString(`(?:`)
+			sub.write(b)
+			b.WriteString(`)`)
+		}
+		switch {
+		case n.op == syntax.OpQuest:
+			b.WriteByte('?')
+		case n.max == -1:
+			fmt.Fprintf(b, "{%d,}", n.min)
+		case n.min == n.max:
+			fmt.Fprintf(b, "{%d}", n.min)
+		default:
+			fmt.Fprintf(b, "{%d,%d}", n.min, n.max)
+		}
+	}
+}
+
+// writeClass writes the character class of the runes in set, a sorted list
+// of pairs of inclusive bounds.
+func writeClass(b *bytes.Buffer, set []rune) {
+	if len(set) == 2 && set[0] == 0 && set[1] == unicode.MaxRune {
+		b.WriteString(`(?s:.)`)
+		return
+	}
+	if len(set) == 2 && set[0] == set[1] {
+		writeRune(b, set[0])
+		return
+	}
+	b.WriteByte('[')
+	for i := 0; i+1 < len(set); i += 2 {
+		writeRune(b, set[i])
+		if set[i] != set[i+1] {
+			b.WriteByte('-')
+			writeRune(b, set[i+1])
+		}
+	}
+	b.WriteByte(']')
+}
+
+// writeRune writes r so that it stands for itself, inside a character class
+// or out of one.
+func writeRune(b *bytes.Buffer, r rune) {
+	switch {
+	case '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+		b.WriteRune(r)
+	case ' ' < r && r <= '~':
+		b.WriteByte('\\')
+		b.WriteRune(r)
+	case r > '~' && unicode.IsPrint(r):
+		b.WriteRune(r)
+	default:
+		fmt.Fprintf(b, `\x{%X}`, r)
+	}
+}
+
+// A converter turns a minimal automaton into a regular expression. Length
+// bounds are enforced by splitting paths in halves, which keeps the
+// expression polynomial in the maximum length.
+type converter struct {
+	d     *dfa
+	edges [][]*node // edges[s][t] matches the runes leading from s to t
+	loops []bool    // whether s only leads to itself
+	reach [][]bool  // reach[s][t] whether some path leads from s to t
+	work  int       // number of splits tried
+	large bool      // whether some subexpression or the work exceeds its bound
+	eqs   map[[3]int]*node
+	les   map[[2]int]*node
+}
+
+func newConverter(a *alphabet, d *dfa) *converter {
+	n := len(d.next)
+	c := converter{
+		d:     d,
+		edges: make([][]*node, n),
+		loops: make([]bool, n),
+		reach: make([][]bool, n),
+		eqs:   map[[3]int]*node{},
+		les:   map[[2]int]*node{},
+	}
+	for s := 0; s < n; s++ {
+		sets := make([][]rune, n)
+		for cl, t := range d.next[s] {
+			if t != dead {
+				sets[t] = append(sets[t], a.classes[cl]...)
+			}
+		}
+		c.edges[s] = make([]*node, n)
+		c.loops[s] = true
+		for t, set := range sets {
+			c.edges[s][t] = class(normalize(set))
+			if t != s && set != nil {
+				c.loops[s] = false
+			}
+		}
+	}
+	for s := 0; s < n; s++ {
+		c.reach[s] = make([]bool, n)
+		c.reach[s][s] = true
+		for stack := []int{s}; len(stack) != 0; {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for t, e := range c.edges[u] {
+				if e != nil && !c.reach[s][t] {
+					c.reach[s][t] = true
+					stack = append(stack, t)
+				}
+			}
+		}
+	}
+	return &c
+}
+
+// normalize sorts the ranges of set and merges adjacent ones.
+func normalize(set []rune) []rune {
+	rr := make(ranges, 0, len(set)/2)
+	for i := 0; i+1 < len(set); i += 2 {
+		rr = append(rr, [2]rune{set[i], set[i+1]})
+	}
+	sort.Sort(rr)
+	var out []rune
+	for _, r := range rr {
+		if k := len(out); k != 0 && out[k-1]+1 >= r[0] {
+			if r[1] > out[k-1] {
+				out[k-1] = r[1]
+			}
+			continue
+		}
+		out = append(out, r[0], r[1])
+	}
+	return out
+}
+
+type ranges [][2]rune
+
+func (rr ranges) Len() int           { return len(rr) }
+func (rr ranges) Less(i, j int) bool { return rr[i][0] < rr[j][0] }
+func (rr ranges) Swap(i, j int)      { rr[i], rr[j] = rr[j], rr[i] }
+
+func (c *converter) convert(min, max int) *node {
+	n := len(c.d.next)
+	if n == 0 || max != 0 && max < min {
+		return nil
+	}
+	var nn []*node
+	for t := 0; t < n; t++ {
+		if max == 0 {
+			nn = append(nn, concat(c.eq(0, t, min), c.star(t)))
+		} else {
+			nn = append(nn, concat(c.eq(0, t, min), c.le(t, max-min)))
+		}
+	}
+	return alternate(nn...)
+}
+
+// split accounts for one more split of a path.
+func (c *converter) split() {
+	if c.work++; c.work > maxWork {
+		c.large = true
+	}
+}
+
+// eq matches the strings of exactly k runes that lead from s to t.
+func (c *converter) eq(s, t, k int) *node {
+	key := [3]int{s, t, k}
+	if n, ok := c.eqs[key]; ok || c.large {
+		return n
+	}
+	var n *node
+	switch {
+	case !c.reach[s][t]:
+	case k == 0 && s == t:
+		n = empty
+	case k == 0:
+	case k == 1:
+		n = c.edges[s][t]
+	case c.loops[s] && s == t:
+		n = repeat(c.edges[s][s], k, k)
+	case c.loops[s]:
+	default:
+		h := (k + 1) / 2
+		var nn []*node
+		for m := range c.d.next {
+			if c.reach[s][m] && c.reach[m][t] {
+				c.split()
+				nn = append(nn, concat(c.eq(s, m, h), c.eq(m, t, k-h)))
+			}
+		}
+		n = alternate(nn...)
+	}
+	c.eqs[key] = n
+	c.large = c.large || n != nil && n.size > maxSize
+	return n
+}
+
+// le matches the strings of at most k runes that lead from s to an accepting
+// state.
+func (c *converter) le(s, k int) *node {
+	key := [2]int{s, k}
+	if n, ok := c.les[key]; ok || c.large {
+		return n
+	}
+	var n *node
+	switch {
+	case k == 0 && c.d.accept[s]:
+		n = empty
+	case k == 0:
+	case c.loops[s] && c.d.accept[s]:
+		n = repeat(c.edges[s][s], 0, k)
+	case c.loops[s]:
+	default:
+		h := (k + 1) / 2
+		nn := []*node{c.le(s, h-1)}
+		for m := range c.d.next {
+			if c.reach[s][m] {
+				c.split()
+				nn = append(nn, concat(c.eq(s, m, h), c.le(m, k-h)))
+			}
+		}
+		n = alternate(nn...)
+	}
+	c.les[key] = n
+	c.large = c.large || n != nil && n.size > maxSize
+	return n
+}
+
+// star matches the strings of any length that lead from s to an accepting
+// state. It solves the corresponding system of language equations by
+// elimination (Arden's lemma), then by back substitution.
+func (c *converter) star(s int) *node {
+	n := len(c.d.next)
+	a := make([][]*node, n)
+	b := make([]*node, n)
+	for i := range a {
+		a[i] = append([]*node(nil), c.edges[i]...)
+		if c.d.accept[i] {
+			b[i] = empty
+		}
+	}
+	for k := n - 1; k >= 0; k-- {
+		if loop := a[k][k]; loop != nil {
+			star := repeat(loop, 0, -1)
+			for t := 0; t < k; t++ {
+				a[k][t] = concat(star, a[k][t])
+			}
+			b[k] = concat(star, b[k])
+			a[k][k] = nil
+		}
+		for i := 0; i < k; i++ {
+			if a[i][k] == nil {
+				continue
+			}
+			for t := 0; t < k; t++ {
+				a[i][t] = alternate(a[i][t], concat(a[i][k], a[k][t]))
+			}
+			b[i] = alternate(b[i], concat(a[i][k], b[k]))
+			a[i][k] = nil
+		}
+	}
+	x := make([]*node, n)
+	for k := 0; k <= s; k++ {
+		nn := []*node{b[k]}
+		for t := 0; t < k; t++ {
+			nn = append(nn, concat(a[k][t], x[t]))
+		}
+		x[k] = alternate(nn...)
+	}
+	return x[s]
+}
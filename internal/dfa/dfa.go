@@ -0,0 +1,640 @@
+// Package dfa compiles username rules into an equivalent regular expression
+// by way of deterministic finite automata.
+package dfa
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Spec describes a set of strings in the same terms as usrname.Rules.
+type Spec struct {
+	Whitelist  []rune // pairs of inclusive bounds; nil means any rune
+	Prefixes   []string
+	Suffixes   []string
+	Substrings []string
+	Pattern    *syntax.Regexp // strings that contain a match are excluded
+	Reserved   []string       // excluded regardless of case
+	MinLength  int
+	MaxLength  int // 0 means unbounded
+}
+
+// ErrTooLarge is returned by Compile when the expression would be too large
+// to be of any use, as happens with many reserved words and a maximum length.
+var ErrTooLarge = errors.New("dfa: resulting regular expression too large")
+
+// Compile returns a regular expression, in RE2 syntax, that matches exactly
+// the (whole) strings described by spec. The expression is not anchored.
+func Compile(spec Spec) (string, error) {
+	var pattern, reserved *nfa
+	sets := [][]rune{}
+	if spec.Whitelist != nil {
+		sets = append(sets, spec.Whitelist)
+	}
+	for _, ss := range [][]string{spec.Prefixes, spec.Suffixes, spec.Substrings} {
+		for _, s := range ss {
+			for _, r := range s {
+				sets = append(sets, []rune{r, r})
+			}
+		}
+	}
+	if spec.Pattern != nil {
+		var err error
+		if pattern, err = newNFA(spec.Pattern); err != nil {
+			return "", err
+		}
+		sets = append(sets, pattern.sets()...)
+	}
+	if len(spec.Reserved) != 0 {
+		var alts []string
+		for _, w := range spec.Reserved {
+			alts = append(alts, regexp.QuoteMeta(w))
+		}
+		re, err := syntax.Parse(`(?i)^(?:`+strings.Join(alts, "|")+`)$`, syntax.Perl)
+		if err != nil {
+			return "", err
+		}
+		if reserved, err = newNFA(re); err != nil {
+			return "", err
+		}
+		sets = append(sets, reserved.sets()...)
+	}
+	a := newAlphabet(sets)
+
+	dd := []*dfa{whitelist(a, spec.Whitelist)}
+	for _, p := range spec.Prefixes {
+		dd = append(dd, notPrefix(a, p))
+	}
+	for _, s := range spec.Suffixes {
+		dd = append(dd, notSuffix(a, s))
+	}
+	for _, s := range spec.Substrings {
+		dd = append(dd, notSubstring(a, s))
+	}
+	if pattern != nil {
+		dd = append(dd, pattern.notMatching(a))
+	}
+	if reserved != nil {
+		dd = append(dd, reserved.notMatching(a))
+	}
+	d := intersect(dd).minimize()
+	c := newConverter(a, d)
+	re := c.convert(spec.MinLength, spec.MaxLength)
+	if c.large || re != nil && re.size > maxSize {
+		return "", ErrTooLarge
+	}
+	return re.String(), nil
+}
+
+// An alphabet partitions all runes into classes, such that every set of
+// runes of interest is a union of classes.
+type alphabet struct {
+	classes [][]rune // pairs of inclusive bounds
+}
+
+func newAlphabet(sets [][]rune) *alphabet {
+	bounds := map[rune]bool{0: true}
+	for _, set := range sets {
+		for i := 0; i+1 < len(set); i += 2 {
+			bounds[set[i]] = true
+			bounds[set[i+1]+1] = true
+		}
+	}
+	var starts []int
+	for b := range bounds {
+		if b <= unicode.MaxRune {
+			starts = append(starts, int(b))
+		}
+	}
+	sort.Ints(starts)
+
+	a := alphabet{}
+	index := map[string]int{}
+	for i, start := range starts {
+		lo, hi := rune(start), rune(unicode.MaxRune)
+		if i+1 < len(starts) {
+			hi = rune(starts[i+1] - 1)
+		}
+		var sig bytes.Buffer
+		for _, set := range sets {
+			if contains(set, lo) {
+				sig.WriteByte('1')
+			} else {
+				sig.WriteByte('0')
+			}
+		}
+		c, ok := index[sig.String()]
+		if !ok {
+			c = len(a.classes)
+			index[sig.String()] = c
+			a.classes = append(a.classes, nil)
+		}
+		a.classes[c] = append(a.classes[c], lo, hi)
+	}
+	return &a
+}
+
+func (a *alphabet) size() int {
+	return len(a.classes)
+}
+
+// classOf returns the class that contains r.
+func (a *alphabet) classOf(r rune) int {
+	for c, set := range a.classes {
+		if contains(set, r) {
+			return c
+		}
+	}
+	panic("dfa: rune outside alphabet") // should never happen
+}
+
+// in reports whether class c is included in set.
+func (a *alphabet) in(c int, set []rune) bool {
+	return contains(set, a.classes[c][0])
+}
+
+func contains(set []rune, r rune) bool {
+	for i := 0; i+1 < len(set); i += 2 {
+		if set[i] <= r && r <= set[i+1] {
+			return true
+		}
+	}
+	return false
+}
+
+// A dfa is a complete deterministic automaton over the classes of an
+// alphabet. State 0 is the initial state and dead is the rejecting sink.
+type dfa struct {
+	next   [][]int
+	accept []bool
+}
+
+const dead = -1
+
+func (d *dfa) add(accept bool, width int) int {
+	row := make([]int, width)
+	for i := range row {
+		row[i] = dead
+	}
+	d.next = append(d.next, row)
+	d.accept = append(d.accept, accept)
+	return len(d.next) - 1
+}
+
+func whitelist(a *alphabet, set []rune) *dfa {
+	d := dfa{}
+	s := d.add(true, a.size())
+	for c := 0; c < a.size(); c++ {
+		if set == nil || a.in(c, set) {
+			d.next[s][c] = s
+		}
+	}
+	return &d
+}
+
+// kmp returns the transition function of the Knuth-Morris-Pratt automaton
+// for lit, whose state i means that the last i runes read match the first i
+// runes of lit.
+func kmp(a *alphabet, lit []int) [][]int {
+	n := len(lit)
+	fail := make([]int, n+1)
+	for i, k := 1, 0; i < n; i++ {
+		for k > 0 && lit[i] != lit[k] {
+			k = fail[k]
+		}
+		if lit[i] == lit[k] {
+			k++
+		}
+		fail[i+1] = k
+	}
+	delta := make([][]int, n+1)
+	for i := range delta {
+		delta[i] = make([]int, a.size())
+		for c := range delta[i] {
+			switch {
+			case i < n && lit[i] == c:
+				delta[i][c] = i + 1
+			case i == 0:
+				delta[i][c] = 0
+			default:
+				delta[i][c] = delta[fail[i]][c]
+			}
+		}
+	}
+	return delta
+}
+
+func classes(a *alphabet, s string) []int {
+	var cc []int
+	for _, r := range s {
+		cc = append(cc, a.classOf(r))
+	}
+	return cc
+}
+
+func notPrefix(a *alphabet, prefix string) *dfa {
+	lit := classes(a, prefix)
+	d := dfa{}
+	for i := 0; i <= len(lit); i++ {
+		d.add(i < len(lit), a.size())
+	}
+	if len(lit) == 0 {
+		return &d
+	}
+	// state len(lit)+1 has escaped the prefix for good
+	free := d.add(true, a.size())
+	for c := 0; c < a.size(); c++ {
+		d.next[free][c] = free
+	}
+	for i := 0; i < len(lit); i++ {
+		for c := 0; c < a.size(); c++ {
+			if c == lit[i] {
+				if i+1 < len(lit) {
+					d.next[i][c] = i + 1
+				}
+			} else {
+				d.next[i][c] = free
+			}
+		}
+	}
+	return &d
+}
+
+func notSuffix(a *alphabet, suffix string) *dfa {
+	lit := classes(a, suffix)
+	delta := kmp(a, lit)
+	d := dfa{}
+	for i := range delta {
+		d.add(i != len(lit), a.size())
+		copy(d.next[i], delta[i])
+	}
+	return &d
+}
+
+func notSubstring(a *alphabet, sub string) *dfa {
+	lit := classes(a, sub)
+	delta := kmp(a, lit)
+	d := dfa{}
+	for i := 0; i < len(lit); i++ {
+		d.add(true, a.size())
+		for c, j := range delta[i] {
+			if j < len(lit) {
+				d.next[i][c] = j
+			}
+		}
+	}
+	if len(lit) == 0 {
+		d.add(false, a.size())
+	}
+	return &d
+}
+
+// intersect returns the product automaton of dd.
+func intersect(dd []*dfa) *dfa {
+	width := len(dd[0].next[0])
+	p := dfa{}
+	index := map[string]int{}
+	var queue [][]int
+	key := func(tuple []int) string {
+		return fmt.Sprint(tuple)
+	}
+	visit := func(tuple []int) int {
+		for _, s := range tuple {
+			if s == dead {
+				return dead
+			}
+		}
+		k := key(tuple)
+		if s, ok := index[k]; ok {
+			return s
+		}
+		accept := true
+		for i, s := range tuple {
+			accept = accept && dd[i].accept[s]
+		}
+		s := p.add(accept, width)
+		index[k] = s
+		queue = append(queue, tuple)
+		return s
+	}
+	visit(make([]int, len(dd)))
+	for s := 0; s < len(queue); s++ {
+		tuple := queue[s]
+		for c := 0; c < width; c++ {
+			next := make([]int, len(tuple))
+			for i, t := range tuple {
+				next[i] = dd[i].next[t][c]
+			}
+			p.next[s][c] = visit(next)
+		}
+	}
+	return &p
+}
+
+// minimize returns the minimal automaton equivalent to d, without the states
+// from which no accepting state can be reached.
+func (d *dfa) minimize() *dfa {
+	n, width := len(d.next), len(d.next[0])
+
+	// prune the states that cannot lead to acceptance
+	live := make([]bool, n)
+	for changed := true; changed; {
+		changed = false
+		for s := 0; s < n; s++ {
+			if live[s] {
+				continue
+			}
+			if d.accept[s] {
+				live[s], changed = true, true
+				continue
+			}
+			for _, t := range d.next[s] {
+				if t != dead && live[t] {
+					live[s], changed = true, true
+					break
+				}
+			}
+		}
+	}
+	target := func(s, c int) int {
+		t := d.next[s][c]
+		if t == dead || !live[t] {
+			return dead
+		}
+		return t
+	}
+
+	// Moore's partition refinement
+	block := make([]int, n)
+	for s := range block {
+		if d.accept[s] {
+			block[s] = 1
+		}
+	}
+	for {
+		index := map[string]int{}
+		next := make([]int, n)
+		for s := 0; s < n; s++ {
+			sig := []int{block[s]}
+			for c := 0; c < width; c++ {
+				if t := target(s, c); t == dead {
+					sig = append(sig, dead)
+				} else {
+					sig = append(sig, block[t])
+				}
+			}
+			k := fmt.Sprint(sig)
+			b, ok := index[k]
+			if !ok {
+				b = len(index)
+				index[k] = b
+			}
+			next[s] = b
+		}
+		stable := true
+		for s := range next {
+			if next[s] != block[s] {
+				stable = false
+			}
+		}
+		block = next
+		if stable {
+			break
+		}
+	}
+
+	// renumber the blocks of live states, starting from the initial state
+	m := dfa{}
+	number := map[int]int{}
+	var order []int
+	if live[0] {
+		number[block[0]] = 0
+		order = append(order, 0)
+		m.add(d.accept[0], width)
+	}
+	for i := 0; i < len(order); i++ {
+		s := order[i]
+		for c := 0; c < width; c++ {
+			t := target(s, c)
+			if t == dead {
+				continue
+			}
+			u, ok := number[block[t]]
+			if !ok {
+				u = m.add(d.accept[t], width)
+				number[block[t]] = u
+				order = append(order, t)
+			}
+			m.next[i][c] = u
+		}
+	}
+	return &m
+}
+
+var errUnsupported = errors.New("dfa: unsupported regular expression")
+
+// An nfa is a Thompson automaton for a regular expression, whose state 0 is
+// initial and state 1 is final.
+type nfa struct {
+	edges [][]edge
+}
+
+type edgeKind int
+
+const (
+	epsilon edgeKind = iota
+	beginText
+	endText
+	runes
+)
+
+type edge struct {
+	kind edgeKind
+	set  []rune
+	to   int
+}
+
+func newNFA(re *syntax.Regexp) (*nfa, error) {
+	n := nfa{}
+	start, final := n.state(), n.state()
+	if err := n.build(re.Simplify(), start, final); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (n *nfa) state() int {
+	n.edges = append(n.edges, nil)
+	return len(n.edges) - 1
+}
+
+func (n *nfa) link(from, to int, kind edgeKind, set []rune) {
+	n.edges[from] = append(n.edges[from], edge{kind, set, to})
+}
+
+func (n *nfa) build(re *syntax.Regexp, from, to int) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+	case syntax.OpEmptyMatch:
+		n.link(from, to, epsilon, nil)
+	case syntax.OpLiteral:
+		cur := from
+		for i, r := range re.Rune {
+			next := to
+			if i+1 < len(re.Rune) {
+				next = n.state()
+			}
+			set := []rune{r, r}
+			if re.Flags&syntax.FoldCase != 0 {
+				for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+					set = append(set, f, f)
+				}
+			}
+			n.link(cur, next, runes, set)
+			cur = next
+		}
+		if len(re.Rune) == 0 {
+			n.link(from, to, epsilon, nil)
+		}
+	case syntax.OpCharClass:
+		n.link(from, to, runes, re.Rune)
+	case syntax.OpAnyCharNotNL:
+		n.link(from, to, runes, []rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune})
+	case syntax.OpAnyChar:
+		n.link(from, to, runes, []rune{0, unicode.MaxRune})
+	case syntax.OpBeginText:
+		n.link(from, to, beginText, nil)
+	case syntax.OpEndText:
+		n.link(from, to, endText, nil)
+	case syntax.OpCapture:
+		return n.build(re.Sub[0], from, to)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		in, out := n.state(), n.state()
+		n.link(from, in, epsilon, nil)
+		n.link(out, to, epsilon, nil)
+		if re.Op != syntax.OpPlus {
+			n.link(from, to, epsilon, nil)
+		}
+		if re.Op != syntax.OpQuest {
+			n.link(out, in, epsilon, nil)
+		}
+		return n.build(re.Sub[0], in, out)
+	case syntax.OpConcat:
+		cur := from
+		for i, sub := range re.Sub {
+			next := to
+			if i+1 < len(re.Sub) {
+				next = n.state()
+			}
+			if err := n.build(sub, cur, next); err != nil {
+				return err
+			}
+			cur = next
+		}
+		if len(re.Sub) == 0 {
+			n.link(from, to, epsilon, nil)
+		}
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if err := n.build(sub, from, to); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%v: %s", errUnsupported, re)
+	}
+	return nil
+}
+
+func (n *nfa) sets() [][]rune {
+	var sets [][]rune
+	for _, ee := range n.edges {
+		for _, e := range ee {
+			if e.kind == runes {
+				sets = append(sets, e.set)
+			}
+		}
+	}
+	return sets
+}
+
+// closure adds to ss the states reachable from them without reading a rune,
+// at the beginning and/or at the end of the text.
+func (n *nfa) closure(ss map[int]bool, begin, end bool) {
+	stack := make([]int, 0, len(ss))
+	for s := range ss {
+		stack = append(stack, s)
+	}
+	for len(stack) != 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range n.edges[s] {
+			ok := e.kind == epsilon ||
+				e.kind == beginText && begin ||
+				e.kind == endText && end
+			if ok && !ss[e.to] {
+				ss[e.to] = true
+				stack = append(stack, e.to)
+			}
+		}
+	}
+}
+
+// notMatching returns an automaton that accepts the strings that contain no
+// match of n.
+func (n *nfa) notMatching(a *alphabet) *dfa {
+	const final = 1
+	d := dfa{}
+	index := map[string]int{}
+	var queue []map[int]bool
+	key := func(ss map[int]bool) string {
+		var ii []int
+		for s := range ss {
+			ii = append(ii, s)
+		}
+		sort.Ints(ii)
+		return fmt.Sprint(ii)
+	}
+	visit := func(ss map[int]bool, begin bool) int {
+		if ss[final] {
+			return dead
+		}
+		k := fmt.Sprint(begin, key(ss))
+		if s, ok := index[k]; ok {
+			return s
+		}
+		atEnd := map[int]bool{}
+		for s := range ss {
+			atEnd[s] = true
+		}
+		n.closure(atEnd, begin, true)
+		s := d.add(!atEnd[final], a.size())
+		index[k] = s
+		queue = append(queue, ss)
+		return s
+	}
+	initial := map[int]bool{0: true}
+	n.closure(initial, true, false)
+	visit(initial, true)
+	for i := 0; i < len(queue); i++ {
+		for c := 0; c < a.size(); c++ {
+			next := map[int]bool{0: true} // a match may start anywhere
+			for s := range queue[i] {
+				for _, e := range n.edges[s] {
+					if e.kind == runes && a.in(c, e.set) {
+						next[e.to] = true
+					}
+				}
+			}
+			n.closure(next, false, false)
+			d.next[i][c] = visit(next, false)
+		}
+	}
+	return &d
+}
@@ -0,0 +1,273 @@
+package internal
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jubobs/usrname"
+)
+
+// ACEPrefix marks A-labels, the ASCII forms of internationalized labels.
+const ACEPrefix = "xn--"
+
+// maxLabel bounds the length, in octets, of DNS labels.
+const maxLabel = 63
+
+// CheckIDNA checks that username is a valid IDNA label (RFC 5891), regardless
+// of case: either an A-label that decodes into a valid U-label, or a U-label
+// whose A-label fits in a DNS label and which does not start with a mark.
+// The other rules of U-labels (letters, marks, digits and hyphens only, and
+// hyphens as in LDH labels) are left to the other checks, except for the
+// U-labels that A-labels decode into. The IDNA tables, which rule out a few
+// more code points, are not taken into account.
+func CheckIDNA() validate1 {
+	return func(username string) usrname.Violation {
+		label := strings.ToLower(username)
+		if !isASCII(label) {
+			r, _ := utf8.DecodeRuneInString(label)
+			if unicode.Is(unicode.M, r) {
+				return &usrname.InvalidLabel{Reason: errLeadingMark.Error()}
+			}
+			a, err := ToASCII(label)
+			if err == nil && len(a) > maxLabel {
+				err = errors.New("A-label longer than 63 octets")
+			}
+			if err != nil {
+				return &usrname.InvalidLabel{Reason: err.Error()}
+			}
+			return nil
+		}
+		if !strings.HasPrefix(label, ACEPrefix) {
+			return nil
+		}
+		u, err := punyDecode(label[len(ACEPrefix):])
+		if err == nil && isASCII(string(u)) {
+			err = errors.New("A-label of an ASCII label")
+		}
+		if err == nil {
+			err = checkULabel(string(u))
+		}
+		if err == nil {
+			if a, _ := ToASCII(string(u)); a != label {
+				err = errors.New("A-label not in canonical form")
+			}
+		}
+		if err != nil {
+			return &usrname.InvalidLabel{Reason: err.Error()}
+		}
+		return nil
+	}
+}
+
+// ToASCII returns the A-label of label, in lowercase, or label itself, in
+// lowercase, if it is made of ASCII characters only.
+func ToASCII(label string) (string, error) {
+	label = strings.ToLower(label)
+	if isASCII(label) {
+		return label, nil
+	}
+	s, err := punyEncode([]rune(label))
+	if err != nil {
+		return "", err
+	}
+	return ACEPrefix + s, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+var errLeadingMark = errors.New("U-label starting with a combining mark")
+
+// checkULabel checks the lowercase U-label u.
+func checkULabel(u string) error {
+	for i, r := range u {
+		switch {
+		case i == 0 && unicode.Is(unicode.M, r):
+			return errLeadingMark
+		case r == '-' || unicode.In(r, unicode.L, unicode.M, unicode.Nd):
+		case r == utf8.RuneError:
+			return errors.New("U-label not in UTF-8")
+		default:
+			return errors.New("U-label with a disallowed code point")
+		}
+	}
+	if rr := []rune(u); len(rr) > 3 && rr[2] == '-' && rr[3] == '-' {
+		return errors.New("U-label with hyphens in the third and fourth positions")
+	}
+	if strings.HasPrefix(u, "-") || strings.HasSuffix(u, "-") {
+		return errors.New("U-label starting or ending with a hyphen")
+	}
+	return nil
+}
+
+// Parameters of Punycode, as set by RFC 3492 for IDNA.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+var errOverflow = errors.New("punycode: overflow")
+
+// punyEncode encodes runes in Punycode (RFC 3492).
+func punyEncode(runes []rune) (string, error) {
+	var out []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	b := len(out)
+	h := b
+	if b > 0 {
+		out = append(out, '-')
+	}
+	n, delta, bias := punyInitialN, 0, punyInitialBias
+	for h < len(runes) {
+		m := math.MaxInt32
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		if m-n > (math.MaxInt32-delta)/(h+1) {
+			return "", errOverflow
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+		for _, r := range runes {
+			if int(r) < n {
+				if delta++; delta == math.MaxInt32 {
+					return "", errOverflow
+				}
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := punyThreshold(k, bias)
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return string(out), nil
+}
+
+// punyDecode decodes s, in lowercase, from Punycode (RFC 3492).
+func punyDecode(s string) ([]rune, error) {
+	var out []rune
+	pos := 0
+	if i := strings.LastIndex(s, "-"); i != -1 {
+		for _, r := range s[:i] {
+			out = append(out, r)
+		}
+		pos = i + 1
+	}
+	n, i, bias := punyInitialN, 0, punyInitialBias
+	for pos < len(s) {
+		old, w := i, 1
+		for k := punyBase; ; k += punyBase {
+			if pos == len(s) {
+				return nil, errors.New("punycode: truncated input")
+			}
+			digit, ok := punyValue(s[pos])
+			if !ok {
+				return nil, errors.New("punycode: invalid digit")
+			}
+			pos++
+			if digit > (math.MaxInt32-i)/w {
+				return nil, errOverflow
+			}
+			i += digit * w
+			t := punyThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			if w > math.MaxInt32/(punyBase-t) {
+				return nil, errOverflow
+			}
+			w *= punyBase - t
+		}
+		bias = punyAdapt(i-old, len(out)+1, old == 0)
+		if i/(len(out)+1) > math.MaxInt32-n {
+			return nil, errOverflow
+		}
+		n += i / (len(out) + 1)
+		i %= len(out) + 1
+		if n > unicode.MaxRune || 0xd800 <= n && n <= 0xdfff {
+			return nil, errors.New("punycode: invalid code point")
+		}
+		out = append(out, 0)
+		copy(out[i+1:], out[i:])
+		out[i] = rune(n)
+		i++
+	}
+	return out, nil
+}
+
+func punyThreshold(k, bias int) int {
+	switch t := k - bias; {
+	case t < punyTMin:
+		return punyTMin
+	case t > punyTMax:
+		return punyTMax
+	default:
+		return t
+	}
+}
+
+func punyAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > (punyBase-punyTMin)*punyTMax/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func punyValue(c byte) (int, bool) {
+	switch {
+	case 'a' <= c && c <= 'z':
+		return int(c - 'a'), true
+	case '0' <= c && c <= '9':
+		return int(c-'0') + 26, true
+	}
+	return 0, false
+}
@@ -1,6 +1,9 @@
 package internal
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
 	"regexp"
 	"strings"
 	"unicode"
@@ -53,11 +56,25 @@ func CheckIllegalPrefix(prefix string) validate1 {
 	}
 }
 
+// CheckIllegalPrefixes reports the first of prefixes that username starts
+// with.
+func CheckIllegalPrefixes(prefixes []string) validate1 {
+	return func(username string) (v usrname.Violation) {
+		for _, prefix := range prefixes {
+			if v = CheckIllegalPrefix(prefix)(username); v != nil {
+				return
+			}
+		}
+		return
+	}
+}
+
 func CheckIllegalSubstring(sub string) validate1 {
 	return func(username string) (v usrname.Violation) {
-		if strings.Contains(username, sub) {
+		if i := strings.Index(username, sub); i != -1 {
 			v = &usrname.IllegalSubstring{
 				Pattern: sub,
+				At:      []int{i, i + len(sub)},
 			}
 		}
 		return
@@ -75,6 +92,18 @@ func CheckIllegalSuffix(suffix string) validate1 {
 	}
 }
 
+// CheckIllegalSuffixes reports the first of suffixes that username ends with.
+func CheckIllegalSuffixes(suffixes []string) validate1 {
+	return func(username string) (v usrname.Violation) {
+		for _, suffix := range suffixes {
+			if v = CheckIllegalSuffix(suffix)(username); v != nil {
+				return
+			}
+		}
+		return
+	}
+}
+
 func CheckNotMatches(re *regexp.Regexp) validate1 {
 	return func(username string) (v usrname.Violation) {
 		if ii := re.FindStringIndex(username); ii != nil {
@@ -87,6 +116,20 @@ func CheckNotMatches(re *regexp.Regexp) validate1 {
 	}
 }
 
+// CheckNotReserved checks that username is none of words, regardless of case.
+func CheckNotReserved(words []string) validate1 {
+	return func(username string) (v usrname.Violation) {
+		for _, w := range words {
+			if strings.EqualFold(username, w) {
+				return &usrname.Reserved{
+					Word: w,
+				}
+			}
+		}
+		return
+	}
+}
+
 func CheckShorterThan(max int) validate1 {
 	return func(username string) (v usrname.Violation) {
 		count := utf8.RuneCountInString(username)
@@ -110,6 +153,24 @@ func CheckAll(username string, fs ...validate1) []usrname.Violation {
 	return vv
 }
 
+// ReadBody reads and closes the body of res, if any.
+func ReadBody(res *http.Response) ([]byte, error) {
+	if res.Body == nil {
+		return nil, nil
+	}
+	defer res.Body.Close()
+	return ioutil.ReadAll(res.Body)
+}
+
+// DecodeJSON decodes the JSON body of res into v.
+func DecodeJSON(res *http.Response, v interface{}) error {
+	body, err := ReadBody(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
 func IsTimeout(err error) bool {
 	type timeout interface {
 		Timeout() bool
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// hostPattern matches DNS names of at least two labels, without a port. IP
+// literals (whose last label is numeric or which contain colons) do not
+// match.
+var hostPattern = regexp.MustCompile(`^(?:[0-9a-z](?:[-0-9a-z]{0,61}[0-9a-z])?\.)+[a-z](?:[-0-9a-z]{0,61}[0-9a-z])?$`)
+
+// privateTLDs lists top-level domains that never designate public hosts.
+var privateTLDs = []string{"arpa", "home", "internal", "lan", "local", "localhost"}
+
+// IsPublicHost reports whether host, in lowercase, is a DNS name that may
+// designate a public host, lest checks be used to reach services on private
+// networks. Names that resolve to private addresses remain possible;
+// servers that check usernames on behalf of others should also use a Client
+// whose dialer refuses such addresses.
+func IsPublicHost(host string) bool {
+	if len(host) > 253 || !hostPattern.MatchString(host) {
+		return false
+	}
+	tld := host[strings.LastIndex(host, ".")+1:]
+	for _, t := range privateTLDs {
+		if tld == t {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNotFound reports whether err is a DNS error saying that the name does
+// not exist; net.DNSError.IsNotFound needs Go 1.13.
+func IsNotFound(err error) bool {
+	dnsErr, ok := err.(*net.DNSError)
+	return ok && dnsErr.Err == "no such host"
+}
+
+// Resolver looks DNS records up through the system's resolver, but gives up
+// once ctx is done; net.DefaultResolver, which does the same, needs Go 1.8.
+type Resolver struct{}
+
+func (Resolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	type answer struct {
+		records []string
+		err     error
+	}
+	c := make(chan answer, 1)
+	go func() {
+		records, err := net.LookupTXT(name)
+		c <- answer{records, err}
+	}()
+	select {
+	case a := <-c:
+		return a.records, a.err
+	case <-ctx.Done():
+		return nil, &net.DNSError{Err: ctx.Err().Error(), Name: name, IsTimeout: true}
+	}
+}
+
+func (Resolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	type answer struct {
+		records []*net.NS
+		err     error
+	}
+	c := make(chan answer, 1)
+	go func() {
+		records, err := net.LookupNS(name)
+		c <- answer{records, err}
+	}()
+	select {
+	case a := <-c:
+		return a.records, a.err
+	case <-ctx.Done():
+		return nil, &net.DNSError{Err: ctx.Err().Error(), Name: name, IsTimeout: true}
+	}
+}
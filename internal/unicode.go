@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Union returns a table of the runes that belong to any of tables.
+func Union(tables ...*unicode.RangeTable) *unicode.RangeTable {
+	var rr [][2]rune
+	add := func(lo, hi, stride rune) {
+		if stride == 1 {
+			rr = append(rr, [2]rune{lo, hi})
+			return
+		}
+		for r := lo; r <= hi; r += stride {
+			rr = append(rr, [2]rune{r, r})
+		}
+	}
+	for _, t := range tables {
+		for _, r := range t.R16 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+		for _, r := range t.R32 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+	}
+	sort.Sort(byLo(rr))
+	var merged [][2]rune
+	for _, r := range rr {
+		if k := len(merged); k != 0 && merged[k-1][1]+1 >= r[0] {
+			if r[1] > merged[k-1][1] {
+				merged[k-1][1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	t := unicode.RangeTable{}
+	for _, r := range merged {
+		if r[0] <= 0xFFFF && r[1] > 0xFFFF {
+			t.R16 = append(t.R16, unicode.Range16{Lo: uint16(r[0]), Hi: 0xFFFF, Stride: 1})
+			r[0] = 0x10000
+		}
+		if r[1] <= 0xFFFF {
+			t.R16 = append(t.R16, unicode.Range16{Lo: uint16(r[0]), Hi: uint16(r[1]), Stride: 1})
+			if r[1] <= unicode.MaxLatin1 {
+				t.LatinOffset++
+			}
+		} else {
+			t.R32 = append(t.R32, unicode.Range32{Lo: uint32(r[0]), Hi: uint32(r[1]), Stride: 1})
+		}
+	}
+	return &t
+}
+
+type byLo [][2]rune
+
+func (rr byLo) Len() int           { return len(rr) }
+func (rr byLo) Less(i, j int) bool { return rr[i][0] < rr[j][0] }
+func (rr byLo) Swap(i, j int)      { rr[i], rr[j] = rr[j], rr[i] }
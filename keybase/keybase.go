@@ -0,0 +1,192 @@
+package keybase
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type keybase struct {
+	name             string
+	scheme           string
+	host             string
+	illegalPrefix    string
+	illegalSubstring string
+	whitelist        *unicode.RangeTable
+	minLength        int
+	maxLength        int
+	metadata         usrname.Metadata
+}
+
+var keybaseImpl = keybase{
+	name:             "Keybase",
+	scheme:           "https",
+	host:             "keybase.io",
+	illegalPrefix:    "_",
+	illegalSubstring: "__",
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 2,
+	maxLength: 16,
+	metadata: usrname.Metadata{
+		Category:  usrname.Code,
+		Tags:      []string{"identity"},
+		Homepage:  "https://keybase.io",
+		RulesURL:  "https://github.com/keybase/client/blob/master/go/libkb/checkers.go",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(keybaseImpl.name, &keybaseImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &keybaseImpl
+}
+
+func (s *keybase) Name() string {
+	return s.name
+}
+
+func (s *keybase) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *keybase) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/" + username,
+	}
+	return u.String()
+}
+
+func (*keybase) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *keybase) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *keybase) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:         v.minLength,
+		MaxLength:         v.maxLength,
+		Whitelist:         v.whitelist,
+		IllegalPrefixes:   []string{v.illegalPrefix},
+		IllegalSubstrings: []string{v.illegalSubstring},
+	}
+}
+
+// Usernames are case-insensitive.
+func (*keybase) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See CheckUsername in https://github.com/keybase/client/blob/master/go/libkb/checkers.go
+func (v *keybase) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefix(v.illegalPrefix),
+		internal.CheckIllegalSubstring(v.illegalSubstring),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *keybase) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			return
+		}
+		// The API mostly reports failures in the body rather than through the
+		// status code; unknown users come back as null entries.
+		var body struct {
+			Status struct {
+				Code int    `json:"code"`
+				Name string `json:"name"`
+			} `json:"status"`
+			Them []*struct {
+				ID string `json:"id"`
+			} `json:"them"`
+		}
+		if err := internal.DecodeJSON(res, &body); err != nil {
+			r.Status = usrname.UnknownStatus
+			r.Message = "unexpected response body"
+			return
+		}
+		switch {
+		case body.Status.Name == "NOT_FOUND":
+			r.Status = usrname.Available
+		case body.Status.Code != 0:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status %s", body.Status.Name)
+		case len(body.Them) == 1 && body.Them[0] == nil:
+			r.Status = usrname.Available
+		case len(body.Them) == 1:
+			r.Status = usrname.Unavailable
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = "unexpected response body"
+		}
+		return
+	}
+}
+
+func (c *keybase) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   "/_/api/1.0/user/lookup.json",
+		RawQuery: url.Values{
+			"usernames": {username},
+			"fields":    {"basics"},
+		}.Encode(),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,162 @@
+package lichess
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type lichess struct {
+	name           string
+	scheme         string
+	host           string
+	illegalPattern *regexp.Regexp
+	whitelist      *unicode.RangeTable
+	minLength      int
+	maxLength      int
+	metadata       usrname.Metadata
+}
+
+var lichessImpl = lichess{
+	name:   "Lichess",
+	scheme: "https",
+	host:   "lichess.org",
+	// must start with a letter and end with a letter or digit, without
+	// consecutive hyphens or underscores
+	illegalPattern: regexp.MustCompile(`^[^A-Za-z]|[-_][-_]|[-_]$`),
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 2,
+	maxLength: 20,
+	metadata: usrname.Metadata{
+		Category:  usrname.Gaming,
+		Tags:      []string{"chess"},
+		Homepage:  "https://lichess.org",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(lichessImpl.name, &lichessImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &lichessImpl
+}
+
+func (s *lichess) Name() string {
+	return s.name
+}
+
+func (s *lichess) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *lichess) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/@/" + username,
+	}
+	return u.String()
+}
+
+func (v *lichess) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *lichess) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *lichess) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:      v.minLength,
+		MaxLength:      v.maxLength,
+		Whitelist:      v.whitelist,
+		IllegalPattern: v.illegalPattern.String(),
+	}
+}
+
+// Usernames are case-insensitive.
+func (*lichess) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://github.com/lichess-org/lila/blob/master/modules/user/src/main/UserForm.scala
+func (v *lichess) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *lichess) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		// Closed accounts are still found; their usernames are never
+		// released.
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+func (c *lichess) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   "/api/user/" + username,
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	req.Header.Set("Accept", "application/json")
+	return req
+}
@@ -0,0 +1,168 @@
+package lichess_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/lichess"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = lichess.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Lichess"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://lichess.org/@/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"a",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    2,
+					Actual: 1,
+				},
+			},
+		}, {
+			"specialchars",
+			"Foo-bar_42",
+			noViolations,
+		}, {
+			"digitprefix",
+			"42foo",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 1},
+				},
+			},
+		}, {
+			"doublehyphen",
+			"foo-_bar",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{3, 5},
+				},
+			},
+		}, {
+			"underscoresuffix",
+			"foobar_",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{6, 7},
+				},
+			},
+		}, {
+			"toolong",
+			"a12345678901234567890",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    20,
+					Actual: 21,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "ok",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusOK),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "notfound",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusNotFound),
+			status:   usrname.Available,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -0,0 +1,170 @@
+package linktree
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type linktree struct {
+	name      string
+	scheme    string
+	host      string
+	notFound  string // marks the soft-404 pages served for nonexistent users
+	found     string // marks the pages of existing profiles
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var linktreeImpl = linktree{
+	name:     "Linktree",
+	scheme:   "https",
+	host:     "linktr.ee",
+	notFound: `"page":"/_error"`,
+	found:    `"account":{"id":`,
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'.', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 3,
+	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"link-in-bio"},
+		Homepage:  "https://linktr.ee",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(linktreeImpl.name, &linktreeImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &linktreeImpl
+}
+
+func (s *linktree) Name() string {
+	return s.name
+}
+
+func (s *linktree) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *linktree) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/" + username,
+	}
+	return u.String()
+}
+
+func (*linktree) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *linktree) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *linktree) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Usernames are case-insensitive.
+func (*linktree) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://help.linktr.ee/en/articles/5434134-changing-your-linktree-username
+func (v *linktree) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *linktree) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Nonexistent users may get the application's error page, served
+			// with status code 200; the page data tells it apart, but its
+			// markers come from hand-written fixtures, not from Linktree, so
+			// they only make the message more specific.
+			body, err := internal.ReadBody(res)
+			notFound := bytes.Contains(body, []byte(c.notFound))
+			found := bytes.Contains(body, []byte(c.found))
+			r.Status = usrname.UnknownStatus
+			switch {
+			case err != nil:
+				r.Message = "unexpected response body"
+			case notFound && !found:
+				r.Message = "error page, going by unverified markup"
+			case found && !notFound:
+				r.Message = "profile page, going by unverified markup"
+			default:
+				r.Message = "no profile data in page"
+			}
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+func (c *linktree) request(username string) *http.Request {
+	req, err := http.NewRequest("GET", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
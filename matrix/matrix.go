@@ -0,0 +1,225 @@
+package matrix
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+// maxUserIDLength bounds the length of user IDs ("@localpart:server").
+const maxUserIDLength = 255
+
+// matrix checks the localparts of user IDs on the homeserver at host.
+type matrix struct {
+	name      string
+	scheme    string
+	host      string
+	path      string // for homeservers served under a relative URL
+	server    string // as it appears in user IDs
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var matrixImpl = newMatrix("Matrix", "https", "matrix-client.matrix.org", "", "matrix.org")
+
+func newMatrix(name, scheme, host, path, server string) matrix {
+	return matrix{
+		name:   name,
+		scheme: scheme,
+		host:   host,
+		path:   path,
+		server: server,
+		whitelist: &unicode.RangeTable{
+			R16: []unicode.Range16{
+				{'+', '+', 1},
+				{'-', '9', 1}, // "-", ".", "/" and digits
+				{'=', '=', 1},
+				{'_', '_', 1},
+				{'a', 'z', 1},
+			},
+		},
+		minLength: 1,
+		maxLength: maxUserIDLength - len("@:"+server),
+		metadata: usrname.Metadata{
+			Category:  usrname.Messaging,
+			Tags:      []string{"chat", "decentralized"},
+			Homepage:  scheme + "://" + host + path,
+			RulesURL:  "https://spec.matrix.org/latest/appendices/#user-identifiers",
+			Probeable: true,
+		},
+	}
+}
+
+func init() {
+	if err := usrname.Register(matrixImpl.name, &matrixImpl); err != nil {
+		panic(err)
+	}
+}
+
+// New returns the Checker for matrix.org.
+func New() usrname.Checker {
+	return &matrixImpl
+}
+
+// NewInstance returns a Checker, named name, for the homeserver at baseURL
+// (e.g. "https://matrix.example.com"), whose server name is the host of
+// baseURL.
+func NewInstance(name string, baseURL string) (usrname.Checker, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("matrix: invalid base URL %q", baseURL)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	c := newMatrix(name, u.Scheme, u.Host, path, u.Host)
+	return &c, nil
+}
+
+// Register registers, under name, a Checker for the homeserver at baseURL
+// and returns it.
+func Register(name string, baseURL string) (usrname.Checker, error) {
+	c, err := NewInstance(name, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := usrname.Register(name, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *matrix) Name() string {
+	return s.name
+}
+
+func (s *matrix) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *matrix) Link(username string) string {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "matrix.to",
+		Path:     "/",
+		Fragment: "/@" + username + ":" + s.server,
+	}
+	return u.String()
+}
+
+func (*matrix) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *matrix) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *matrix) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Homeservers lowercase localparts upon registration.
+func (*matrix) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://spec.matrix.org/latest/appendices/#user-identifiers
+func (v *matrix) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *matrix) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+
+		// The homeserver answers in JSON whatever the outcome.
+		var body struct {
+			Available bool   `json:"available"`
+			ErrCode   string `json:"errcode"`
+			Error     string `json:"error"`
+		}
+		if err := internal.DecodeJSON(res, &body); err != nil {
+			r.Status = usrname.UnknownStatus
+			if res.StatusCode == http.StatusOK {
+				r.Message = "unexpected response body"
+			} else {
+				r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			}
+			return
+		}
+		switch {
+		case res.StatusCode == http.StatusOK && body.Available:
+			r.Status = usrname.Available
+		case body.ErrCode == "M_USER_IN_USE":
+			r.Status = usrname.Unavailable
+		case body.ErrCode == "M_EXCLUSIVE": // reserved for an application service
+			r.Status = usrname.Unavailable
+			r.Message = body.Error
+		case body.ErrCode == "M_INVALID_USERNAME":
+			r.Status = usrname.Invalid
+			r.Message = body.Error
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			if body.ErrCode != "" {
+				r.Message += ": " + body.ErrCode
+			}
+		}
+		return
+	}
+}
+
+// request asks the homeserver itself whether username is available for
+// registration, rather than inferring it from some profile page.
+func (c *matrix) request(username string) *http.Request {
+	u := url.URL{
+		Scheme:   c.scheme,
+		Host:     c.host,
+		Path:     c.path + "/_matrix/client/v3/register/available",
+		RawQuery: url.Values{"username": {username}}.Encode(),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
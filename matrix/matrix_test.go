@@ -0,0 +1,243 @@
+package matrix_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/matrix"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+var checker = matrix.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Matrix"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://matrix.to/#/@" + username + ":matrix.org"
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestNewInstance(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := matrix.NewInstance("ACME Matrix", "https://chat.example.com/")
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	const template = "got %q, want %q"
+	if actual, expected := c.Name(), "ACME Matrix"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := c.Link("foobar"), "https://matrix.to/#/@foobar:chat.example.com"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	// User IDs, server name included, are at most 255 characters long.
+	if actual, expected := c.Rules().MaxLength, 255-len("@:chat.example.com"); actual != expected {
+		t.Errorf("MaxLength, got %d, want %d", actual, expected)
+	}
+	if _, err := matrix.NewInstance("relative", "example.com"); err == nil {
+		t.Errorf("NewInstance with relative URL, got no error, want one")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer leaktest.Check(t)()
+	c, err := matrix.Register("Matrix A", "https://a.example.com")
+	if err != nil {
+		t.Fatalf("Register(%q), unexpected error %v", "Matrix A", err)
+	}
+	if actual, _ := usrname.CheckerFor("Matrix A"); actual != c {
+		t.Errorf("CheckerFor(%q), got %v, want %v", "Matrix A", actual, c)
+	}
+	if _, err := matrix.Register("Matrix", "https://matrix.org"); err == nil {
+		t.Errorf("Register(%q), got no error, want one", "Matrix")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"specialchars",
+			"foo.bar_baz-42=+/",
+			noViolations,
+		}, {
+			"uppercase",
+			"fooBar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"colon",
+			"foo:bar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"toolong",
+			strings.Repeat("a", 244),
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    243,
+					Actual: 244,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_matrix/client/v3/register/available" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("username") {
+		case "free":
+			w.Write([]byte(`{"available":true}`))
+		case "taken":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errcode":"M_USER_IN_USE","error":"User ID already taken."}`))
+		case "bridged":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errcode":"M_EXCLUSIVE","error":"User ID reserved by an application service."}`))
+		case "_reserved":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errcode":"M_INVALID_USERNAME","error":"User ID may not begin with _"}`))
+		case "limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errcode":"M_LIMIT_EXCEEDED","error":"Too Many Requests","retry_after_ms":2000}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+	instance, err := matrix.NewInstance("test", ts.URL)
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "Obviously invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "available",
+			username: "free",
+			client:   usrname.NewClient(),
+			status:   usrname.Available,
+		}, {
+			label:    "inuse",
+			username: "taken",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "exclusive",
+			username: "bridged",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "invalidusername", // per the homeserver's own policy
+			username: "_reserved",
+			client:   usrname.NewClient(),
+			status:   usrname.Invalid,
+		}, {
+			label:    "ratelimited",
+			username: "limited",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than JSON
+			username: "broken",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := instance.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/jubobs/usrname"
@@ -18,6 +19,7 @@ type medium struct {
 	whitelist *unicode.RangeTable
 	minLength int
 	maxLength int
+	metadata  usrname.Metadata
 }
 
 var mediumImpl = medium{
@@ -35,6 +37,12 @@ var mediumImpl = medium{
 	},
 	minLength: 1,
 	maxLength: 16,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"blogging"},
+		Homepage:  "https://medium.com",
+		Probeable: false,
+	},
 }
 
 func init() {
@@ -51,6 +59,10 @@ func (s *medium) Name() string {
 	return s.name
 }
 
+func (s *medium) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
 func (*medium) Link(username string) string {
 	u := url.URL{
 		Scheme: mediumImpl.scheme,
@@ -68,6 +80,19 @@ func (v *medium) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *medium) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Usernames are case-insensitive.
+func (*medium) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
 // See https://help.medium.com/en/managing-your-account/medium-username-rules
 func (v *medium) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
@@ -99,6 +99,20 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	expected := &usrname.Rules{
+		MinLength: 1,
+		MaxLength: 16,
+		Whitelist: checker.Whitelist(),
+	}
+	actual := checker.Rules()
+	if !reflect.DeepEqual(actual, expected) {
+		template := "got %#v, want %#v"
+		t.Errorf(template, actual, expected)
+	}
+}
+
 func TestCheck(t *testing.T) {
 	defer leaktest.Check(t)()
 
@@ -0,0 +1,83 @@
+package usrname
+
+type Category string
+
+const (
+	Social    Category = "social"
+	Code      Category = "code"
+	Packages  Category = "packages"
+	Gaming    Category = "gaming"
+	Creative  Category = "creative"
+	Domains   Category = "domains"
+	Messaging Category = "messaging"
+)
+
+// Metadata describes a site to those who pick checkers or document them.
+type Metadata struct {
+	Category Category
+	Tags     []string
+	Homepage string
+	// RulesURL, if not empty, is that of the site's documentation of its
+	// username rules.
+	RulesURL string
+	// Probeable tells whether checks tell available usernames from
+	// unavailable ones reliably, through an API or through status codes,
+	// rather than by scraping pages meant for people.
+	Probeable bool
+	// LastVerified, if not empty, is the date (YYYY-MM-DD) on which the
+	// rules were last reviewed against the site's documentation (RulesURL,
+	// if any). It says nothing of the markers that checks look for in
+	// pages.
+	LastVerified string
+}
+
+// Copy returns a copy of m that shares no slice with it, so that Metadata
+// methods can hand out their site's metadata without letting callers change
+// it.
+func (m *Metadata) Copy() *Metadata {
+	c := *m
+	c.Tags = append([]string(nil), m.Tags...)
+	return &c
+}
+
+// HasTag reports whether m is tagged with tag.
+func (m *Metadata) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// A Filter tells whether to keep the site described by a Metadata.
+type Filter func(*Metadata) bool
+
+// InCategory keeps the sites in category c.
+func InCategory(c Category) Filter {
+	return func(m *Metadata) bool {
+		return m.Category == c
+	}
+}
+
+// Tagged keeps the sites tagged with tag.
+func Tagged(tag string) Filter {
+	return func(m *Metadata) bool {
+		return m.HasTag(tag)
+	}
+}
+
+// Probeable keeps the sites whose checks are reliable.
+func Probeable(m *Metadata) bool {
+	return m.Probeable
+}
+
+func keep(s Site, filters []Filter) bool {
+	m := s.Metadata()
+	for _, f := range filters {
+		if !f(m) {
+			return false
+		}
+	}
+	return true
+}
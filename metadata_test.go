@@ -0,0 +1,81 @@
+package usrname_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+)
+
+func TestMetadata(t *testing.T) {
+	defer leaktest.Check(t)()
+	for _, name := range usrname.Checkers() {
+		c, _ := usrname.CheckerFor(name)
+		m := c.Metadata()
+		if m.Category == "" {
+			t.Errorf("%s, missing category", name)
+		}
+		if m.Homepage == "" {
+			t.Errorf("%s, missing homepage", name)
+		}
+		if _, err := time.Parse("2006-01-02", m.LastVerified); m.LastVerified != "" && err != nil {
+			t.Errorf("%s, invalid last-verified date %q", name, m.LastVerified)
+		}
+		if len(m.Tags) != 0 {
+			tag := m.Tags[0]
+			m.Tags[0] = "changed"
+			if actual := c.Metadata().Tags[0]; actual != tag {
+				t.Errorf("%s, Metadata shares its tags, got %q, want %q", name, actual, tag)
+			}
+		}
+	}
+}
+
+func TestCheckersWhere(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		label    string
+		filters  []usrname.Filter
+		expected []string
+	}{
+		{
+			"code",
+			[]usrname.Filter{usrname.InCategory(usrname.Code)},
+			[]string{
+				"Codeberg",
+				"Dev.to",
+				"GitHub",
+				"GitLab",
+				"Hacker News",
+				"Keybase",
+				"Stack Overflow",
+			},
+		}, {
+			"codehosting",
+			[]usrname.Filter{usrname.Tagged("code-hosting")},
+			[]string{"Codeberg", "GitHub", "GitLab"},
+		}, {
+			"unreliablegaming",
+			[]usrname.Filter{
+				usrname.InCategory(usrname.Gaming),
+				func(m *usrname.Metadata) bool { return !m.Probeable },
+			},
+			[]string{"Steam"},
+		}, {
+			"none",
+			[]usrname.Filter{usrname.InCategory(usrname.Code), usrname.Tagged("chess")},
+			nil,
+		},
+	}
+	const template = "Checkers(%s), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			actual := usrname.Checkers(c.filters...)
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf(template, c.label, actual, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package mockclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/jubobs/usrname"
+)
+
+// maxRecordedBody bounds the size of recorded bodies; checks seldom need
+// more than the beginning of a page.
+const maxRecordedBody = 256 << 10
+
+// sensitiveHeaders lists the headers that Recording leaves out of
+// cassettes, lest credentials end up in checked-in fixtures.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Private-Token",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"Set-Cookie2",
+}
+
+// ErrTruncated is the error with which Replaying's bodies end when the
+// recorded body was truncated, instead of io.EOF.
+var ErrTruncated = errors.New("mockclient: recorded body was truncated")
+
+// An Exchange is a request and the response to it, as recorded in a
+// cassette file.
+type Exchange struct {
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"requestHeader,omitempty"`
+	RequestBody   string      `json:"requestBody,omitempty"`
+	Status        int         `json:"status"`
+	Header        http.Header `json:"header,omitempty"`
+	Body          string      `json:"body,omitempty"`
+	Truncated     bool        `json:"truncated,omitempty"`
+}
+
+// A Recorder passes requests on to a client, typically a real one, and
+// records the exchanges until Save writes them to a cassette file.
+type Recorder struct {
+	client    usrname.Client
+	path      string
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// Recording returns a Recorder that passes requests on to client and whose
+// Save method writes the exchanges, as JSON, to the cassette file at path,
+// which it overwrites. Requests that fail are not recorded, nor are
+// sensitive headers such as Cookie and Set-Cookie.
+func Recording(client usrname.Client, path string) *Recorder {
+	return &Recorder{client: client, path: path}
+}
+
+// Do passes req on and records the exchange.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	if res.Body != nil {
+		body, err = ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	e := Exchange{
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		RequestHeader: withoutSensitive(req.Header),
+		RequestBody:   string(reqBody),
+		Status:        res.StatusCode,
+		Header:        withoutSensitive(res.Header),
+	}
+	if len(body) > maxRecordedBody {
+		body = body[:maxRecordedBody]
+		e.Truncated = true
+	}
+	e.Body = string(body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, e)
+	return res, nil
+}
+
+// Save writes the exchanges recorded so far to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := json.MarshalIndent(r.exchanges, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+// withoutSensitive returns a copy of h without sensitive headers, or nil if
+// nothing is left.
+func withoutSensitive(h http.Header) http.Header {
+	var c http.Header
+	for k, vv := range h {
+		if isSensitive(k) {
+			continue
+		}
+		if c == nil {
+			c = make(http.Header)
+		}
+		c[k] = append([]string(nil), vv...)
+	}
+	return c
+}
+
+func isSensitive(key string) bool {
+	for _, k := range sensitiveHeaders {
+		if http.CanonicalHeaderKey(key) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Replaying serves back the exchanges recorded in the cassette file at path.
+// Each exchange is served once, in the order of recording among those that
+// match the request: same method, URL and body, and the recorded request
+// headers among those of the request. Requests that match none left fail.
+// Bodies that were truncated when recorded end with ErrTruncated rather than
+// io.EOF.
+func Replaying(path string) usrname.Client {
+	var mu sync.Mutex
+	var exchanges []Exchange
+	loaded := false
+	do := func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !loaded {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(b, &exchanges); err != nil {
+				return nil, fmt.Errorf("mockclient: invalid cassette %s: %v", path, err)
+			}
+			loaded = true
+		}
+		var reqBody []byte
+		if req.Body != nil {
+			var err error
+			reqBody, err = ioutil.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		u := req.URL.String()
+		for i, e := range exchanges {
+			if e.Method != req.Method || e.URL != u || e.RequestBody != string(reqBody) {
+				continue
+			}
+			if !hasHeaders(req.Header, e.RequestHeader) {
+				continue
+			}
+			exchanges = append(exchanges[:i:i], exchanges[i+1:]...)
+			res := http.Response{
+				Status:     fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+				StatusCode: e.Status,
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     e.Header,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(e.Body))),
+				Request:    req,
+			}
+			if e.Truncated {
+				r := io.MultiReader(bytes.NewReader([]byte(e.Body)), errReader{ErrTruncated})
+				res.Body = ioutil.NopCloser(r)
+			}
+			if res.Header == nil {
+				res.Header = make(http.Header)
+			}
+			return &res, nil
+		}
+		return nil, fmt.Errorf("mockclient: no recorded exchange left for %s %s", req.Method, u)
+	}
+	return clientFunc(do)
+}
+
+// hasHeaders reports whether h has every header in want, with the same
+// values.
+func hasHeaders(h http.Header, want http.Header) bool {
+	for k, vv := range want {
+		got := h[http.CanonicalHeaderKey(k)]
+		if len(got) != len(vv) {
+			return false
+		}
+		for i := range vv {
+			if got[i] != vv[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,150 @@
+package mockclient_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname/internal"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+func TestRecordingAndReplaying(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/foobar":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Set-Cookie", "session=s3cr3t")
+			w.Write([]byte("<title>foobar</title>"))
+		case "/huge":
+			w.Write([]byte(strings.Repeat("a", 1<<20)))
+		case "/echo":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write([]byte(r.Header.Get("Accept") + ":"))
+			w.Write(body)
+		default:
+			http.Redirect(w, r, "/", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+	live := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer live.CloseIdleConnections()
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	type exchange struct {
+		method  string
+		path    string
+		accept  string
+		reqBody string
+		status  int
+		body    string
+	}
+	newRequest := func(e exchange) *http.Request {
+		var body io.Reader
+		if e.reqBody != "" {
+			body = strings.NewReader(e.reqBody)
+		}
+		req, _ := http.NewRequest(e.method, ts.URL+e.path, body)
+		if e.accept != "" {
+			req.Header.Set("Accept", e.accept)
+		}
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+		return req
+	}
+	exchanges := []exchange{
+		{"GET", "/foobar", "", "", http.StatusOK, "<title>foobar</title>"},
+		{"HEAD", "/nobody", "", "", http.StatusFound, ""},
+		{"GET", "/foobar", "", "", http.StatusOK, "<title>foobar</title>"},
+		{"POST", "/echo", "", "alpha", http.StatusOK, ":alpha"},
+		{"POST", "/echo", "", "beta", http.StatusOK, ":beta"},
+		{"GET", "/echo", "text/html", "", http.StatusOK, "text/html:"},
+		{"GET", "/echo", "application/json", "", http.StatusOK, "application/json:"},
+	}
+	recorder := mockclient.Recording(live, path)
+	for _, e := range exchanges {
+		res, err := recorder.Do(newRequest(e))
+		if err != nil {
+			t.Fatalf("Recording, %s %s, unexpected error %v", e.method, e.path, err)
+		}
+		if body, _ := internal.ReadBody(res); string(body) != e.body {
+			t.Errorf("Recording, %s %s, got body %q, want %q", e.method, e.path, body, e.body)
+		}
+	}
+	req, _ := http.NewRequest("GET", ts.URL+"/huge", nil)
+	if res, err := recorder.Do(req); err != nil {
+		t.Fatalf("Recording, unexpected error %v", err)
+	} else if body, _ := internal.ReadBody(res); len(body) != 1<<20 {
+		t.Errorf("Recording, got %d bytes, want the whole body", len(body))
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Recording, got cassette before Save, want none")
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save, unexpected error %v", err)
+	}
+	if b, _ := ioutil.ReadFile(path); bytes.Contains(b, []byte("s3cr3t")) {
+		t.Errorf("Recording, got credentials in cassette, want none")
+	}
+
+	replayer := mockclient.Replaying(path)
+	for i := len(exchanges) - 1; i >= 0; i-- { // order matters only among matches
+		e := exchanges[i]
+		res, err := replayer.Do(newRequest(e))
+		if err != nil {
+			t.Fatalf("Replaying, %s %s, unexpected error %v", e.method, e.path, err)
+		}
+		if res.StatusCode != e.status {
+			t.Errorf("Replaying, %s %s, got status %d, want %d", e.method, e.path, res.StatusCode, e.status)
+		}
+		if body, _ := internal.ReadBody(res); string(body) != e.body {
+			t.Errorf("Replaying, %s %s, got body %q, want %q", e.method, e.path, body, e.body)
+		}
+	}
+	req, _ = http.NewRequest("GET", ts.URL+"/huge", nil)
+	if res, err := replayer.Do(req); err != nil {
+		t.Fatalf("Replaying, unexpected error %v", err)
+	} else if body, err := ioutil.ReadAll(res.Body); err != mockclient.ErrTruncated || len(body) >= 1<<20 {
+		t.Errorf("Replaying, got %d bytes and error %v, want a truncated body and ErrTruncated", len(body), err)
+	}
+	req, _ = http.NewRequest("GET", ts.URL+"/foobar", nil)
+	if res, _ := mockclient.Replaying(path).Do(req); res.Header.Get("Content-Type") != "text/html" {
+		t.Errorf("Replaying, got Content-Type %q, want the recorded one", res.Header.Get("Content-Type"))
+	}
+
+	if _, err := replayer.Do(newRequest(exchanges[0])); err == nil {
+		t.Errorf("Replaying, GET /foobar a third time, got no error, want one")
+	}
+	fresh := mockclient.Replaying(path)
+	for _, e := range []exchange{
+		{"GET", "/nobody", "", "", 0, ""},         // recorded for HEAD
+		{"POST", "/echo", "", "gamma", 0, ""},     // recorded with other bodies
+		{"GET", "/echo", "text/plain", "", 0, ""}, // recorded with other headers
+		{"GET", "/unknown", "", "", 0, ""},
+	} {
+		if _, err := fresh.Do(newRequest(e)); err == nil {
+			t.Errorf("Replaying, %s %s, got no error, want one", e.method, e.path)
+		}
+	}
+	if _, err := mockclient.Replaying(filepath.Join(dir, "missing.json")).Do(req); err == nil {
+		t.Errorf("Replaying missing cassette, got no error, want one")
+	}
+}
@@ -1,6 +1,9 @@
 package mockclient
 
 import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/jubobs/usrname"
@@ -42,3 +45,26 @@ func WithStatusCodeAndHeader(sc int, h string, v string) usrname.Client {
 	}
 	return clientFunc(do)
 }
+
+// WithResponseFile responds with the HTTP response stored, in wire format, in
+// the file at path (as dumped by httputil.DumpResponse or curl -i, say).
+func WithResponseFile(path string) usrname.Client {
+	do := func(req *http.Request) (*http.Response, error) {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return res, nil
+	}
+	return clientFunc(do)
+}
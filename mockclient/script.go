@@ -0,0 +1,138 @@
+package mockclient
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is a timeout error, as internal.IsTimeout understands it.
+var ErrTimeout error = timeoutError{}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string {
+	return "mockclient: timeout"
+}
+
+func (timeoutError) Timeout() bool {
+	return true
+}
+
+// A Response is what a Script answers a request with.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+	// Latency delays the answer, unless the context of the request is done
+	// first.
+	Latency time.Duration
+	// Err, if not nil, is returned instead of a response (e.g. ErrTimeout).
+	Err error
+}
+
+// A Script is a Client that answers requests with scripted responses, routed
+// by method and URL, and that records the requests it receives. Scripts are
+// safe for concurrent use.
+type Script struct {
+	mu       sync.Mutex
+	routes   []*route
+	requests []*http.Request
+}
+
+type route struct {
+	method    string
+	url       string
+	responses []Response
+	served    int
+}
+
+func NewScript() *Script {
+	return &Script{}
+}
+
+// On scripts the responses to requests for method and url; an empty method
+// or url matches any. Successive matching requests get successive responses
+// (e.g. failures followed by a success), the last of which repeats. Routes
+// are tried in the order in which they were scripted.
+func (s *Script) On(method string, url string, responses ...Response) *Script {
+	if len(responses) == 0 {
+		panic("mockclient: On called without responses")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := route{
+		method:    method,
+		url:       url,
+		responses: responses,
+	}
+	s.routes = append(s.routes, &r)
+	return s
+}
+
+// Requests returns the requests that s received, in order.
+func (s *Script) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// Do fails for requests that match no route.
+func (s *Script) Do(req *http.Request) (*http.Response, error) {
+	resp, ok := s.next(req)
+	if !ok {
+		return nil, fmt.Errorf("mockclient: no scripted response for %s %s", req.Method, req.URL)
+	}
+	if resp.Latency > 0 {
+		t := time.NewTimer(resp.Latency)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	header := make(http.Header)
+	for k, vv := range resp.Header {
+		header[k] = append([]string(nil), vv...)
+	}
+	res := http.Response{
+		Status:     fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode: resp.StatusCode,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(resp.Body))),
+		Request:    req,
+	}
+	return &res, nil
+}
+
+// next records req and returns the response that its route has next.
+func (s *Script) next(req *http.Request) (Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	for _, r := range s.routes {
+		if r.method != "" && r.method != req.Method {
+			continue
+		}
+		if r.url != "" && r.url != req.URL.String() {
+			continue
+		}
+		i := r.served
+		if i >= len(r.responses) {
+			i = len(r.responses) - 1
+		}
+		r.served++
+		return r.responses[i], true
+	}
+	return Response{}, false
+}
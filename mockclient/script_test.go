@@ -0,0 +1,86 @@
+package mockclient_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname/internal"
+	"github.com/jubobs/usrname/mockclient"
+)
+
+func TestScript(t *testing.T) {
+	defer leaktest.Check(t)()
+	s := mockclient.NewScript().
+		On("GET", "https://example.com/retry",
+			mockclient.Response{StatusCode: http.StatusTooManyRequests},
+			mockclient.Response{Err: mockclient.ErrTimeout},
+			mockclient.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       `{"ok":true}`,
+			},
+		).
+		On("HEAD", "", mockclient.Response{StatusCode: http.StatusNotFound})
+
+	get, _ := http.NewRequest("GET", "https://example.com/retry", nil)
+	if res, err := s.Do(get); err != nil || res.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("first GET, got %v, %v, want status code 429", res, err)
+	}
+	if _, err := s.Do(get); !internal.IsTimeout(err) {
+		t.Errorf("second GET, got error %v, want a timeout", err)
+	}
+	for i := 0; i < 2; i++ { // the last response repeats
+		res, err := s.Do(get)
+		if err != nil || res.StatusCode != http.StatusOK {
+			t.Fatalf("GET after retries, got %v, %v, want status code 200", res, err)
+		}
+		if ct := res.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("GET after retries, got Content-Type %q, want %q", ct, "application/json")
+		}
+		if body, _ := internal.ReadBody(res); string(body) != `{"ok":true}` {
+			t.Errorf("GET after retries, got body %q", body)
+		}
+	}
+
+	head, _ := http.NewRequest("HEAD", "https://example.com/anything", nil)
+	if res, err := s.Do(head); err != nil || res.StatusCode != http.StatusNotFound {
+		t.Errorf("HEAD, got %v, %v, want status code 404", res, err)
+	}
+	post, _ := http.NewRequest("POST", "https://example.com/retry", nil)
+	if _, err := s.Do(post); err == nil {
+		t.Errorf("unscripted POST, got no error, want one")
+	}
+
+	reqs := s.Requests()
+	if len(reqs) != 6 {
+		t.Fatalf("got %d requests, want 6", len(reqs))
+	}
+	if reqs[4].Method != "HEAD" || reqs[4].URL.String() != "https://example.com/anything" {
+		t.Errorf("got request %s %s, want HEAD https://example.com/anything", reqs[4].Method, reqs[4].URL)
+	}
+}
+
+func TestScriptLatency(t *testing.T) {
+	defer leaktest.Check(t)()
+	const latency = 50 * time.Millisecond
+	s := mockclient.NewScript().
+		On("", "", mockclient.Response{StatusCode: http.StatusOK, Latency: latency})
+
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	start := time.Now()
+	if _, err := s.Do(req); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("answered after %v, want at least %v", elapsed, latency)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), latency/10)
+	defer cancel()
+	if _, err := s.Do(req.WithContext(ctx)); !internal.IsTimeout(err) {
+		t.Errorf("with a shorter deadline, got error %v, want a timeout", err)
+	}
+}
@@ -0,0 +1,223 @@
+package npm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+// npm checks either package names or organization names, which serve as
+// scopes for packages (as in "@scope/package").
+type npm struct {
+	name            string
+	scheme          string
+	host            string
+	linkPrefix      string
+	apiHost         string
+	apiPrefix       string
+	apiSuffix       string
+	illegalPrefixes []string
+	reserved        []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+var whitelist = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{'-', '.', 1},
+		{'0', '9', 1},
+		{'_', '_', 1},
+		{'a', 'z', 1},
+	},
+}
+
+var npmImpl = npm{
+	name:            "npm",
+	scheme:          "https",
+	host:            "www.npmjs.com",
+	linkPrefix:      "/package/",
+	apiHost:         "registry.npmjs.org",
+	apiPrefix:       "/",
+	illegalPrefixes: []string{".", "_"},
+	reserved: []string{
+		"favicon.ico", "node_modules",
+		// Node.js core modules
+		"assert", "buffer", "child_process", "cluster", "console",
+		"constants", "crypto", "dgram", "dns", "domain", "events", "fs",
+		"http", "http2", "https", "module", "net", "os", "path", "process",
+		"punycode", "querystring", "readline", "repl", "stream",
+		"string_decoder", "sys", "timers", "tls", "tty", "url", "util", "v8",
+		"vm", "worker_threads", "zlib",
+	},
+	whitelist: whitelist,
+	minLength: 1,
+	maxLength: 214,
+	metadata: usrname.Metadata{
+		Category:  usrname.Packages,
+		Tags:      []string{"javascript"},
+		Homepage:  "https://www.npmjs.com",
+		RulesURL:  "https://github.com/npm/validate-npm-package-name",
+		Probeable: true,
+	},
+}
+
+var orgImpl = npm{
+	name:            "npm orgs",
+	scheme:          "https",
+	host:            "www.npmjs.com",
+	linkPrefix:      "/org/",
+	apiHost:         "registry.npmjs.org",
+	apiPrefix:       "/-/org/",
+	apiSuffix:       "/package",
+	illegalPrefixes: []string{".", "_"},
+	whitelist:       whitelist,
+	minLength:       1,
+	maxLength:       214,
+	metadata: usrname.Metadata{
+		Category:  usrname.Packages,
+		Tags:      []string{"javascript", "organizations"},
+		Homepage:  "https://www.npmjs.com",
+		RulesURL:  "https://github.com/npm/validate-npm-package-name",
+		Probeable: true,
+	},
+}
+
+func init() {
+	for _, c := range []*npm{&npmImpl, &orgImpl} {
+		if err := usrname.Register(c.name, c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// New returns the Checker for npm package names.
+func New() usrname.Checker {
+	return &npmImpl
+}
+
+// NewOrgs returns the Checker for npm organizations, whose names are given
+// without the leading "@" of the scopes they own.
+func NewOrgs() usrname.Checker {
+	return &orgImpl
+}
+
+func (s *npm) Name() string {
+	return s.name
+}
+
+func (s *npm) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *npm) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   s.linkPrefix + username,
+	}
+	return u.String()
+}
+
+func (*npm) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *npm) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *npm) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		Reserved:        append([]string(nil), v.reserved...),
+	}
+}
+
+// The registry rejects new names that differ from existing ones only by case
+// or punctuation.
+func (*npm) Canonicalize(username string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune("-._", r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, username)
+}
+
+// See https://github.com/npm/validate-npm-package-name
+func (v *npm) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckNotReserved(v.reserved),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *npm) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			// Only the exact spelling is known to be free; the registry
+			// offers no lookup by canonical form.
+			r.Status = usrname.UnknownStatus
+			const templ = "%q is free on %s, but names that differ from it only in case or punctuation may not be"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+// request queries the registry, which answers in JSON, rather than the
+// website.
+func (c *npm) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.apiHost,
+		Path:   c.apiPrefix + username + c.apiSuffix,
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
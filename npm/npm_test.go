@@ -0,0 +1,191 @@
+package npm_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/mockclient"
+	"github.com/jubobs/usrname/npm"
+)
+
+var checker = npm.New()
+var orgs = npm.NewOrgs()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const template = "got %q, want %q"
+	if actual, expected := checker.Name(), "npm"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := orgs.Name(), "npm orgs"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const template = "got %q, want %q"
+	if actual, expected := checker.Link(username), "https://www.npmjs.com/package/"+username; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := orgs.Link(username), "https://www.npmjs.com/org/"+username; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"specialchars",
+			"foo.bar-baz_qux",
+			noViolations,
+		}, {
+			"uppercase",
+			"fooBar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"periodprefix",
+			".bar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"coremodule",
+			"http",
+			[]usrname.Violation{
+				&usrname.Reserved{
+					Word: "http",
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+	if vv := orgs.Validate("http"); len(vv) != 0 {
+		t.Errorf(template, "http", vv, noViolations)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		username  string
+		canonical string
+	}{
+		{"foobar", "foobar"},
+		{"Foo.Bar", "foobar"},
+		{"foo-bar_", "foobar"},
+	}
+	const template = "Canonicalize(%q), got %q, want %q"
+	for _, c := range cases {
+		if actual := checker.Canonicalize(c.username); actual != c.canonical {
+			t.Errorf(template, c.username, actual, c.canonical)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "notfound",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusNotFound),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "ok",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusOK),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			for _, checker := range []usrname.Checker{checker, orgs} {
+				res := checker.Check(c.client)(c.username)
+				actual := res.Status
+				expected := c.status
+				if actual != expected {
+					t.Errorf(template, c.username, actual, expected)
+				}
+			}
+		})
+	}
+	const message = `"dummy" is free on %s, but names that differ from it only in case or punctuation may not be`
+	for _, checker := range []usrname.Checker{checker, orgs} {
+		res := checker.Check(mockclient.WithStatusCode(http.StatusNotFound))("dummy")
+		if expected := fmt.Sprintf(message, checker.Name()); res.Message != expected {
+			t.Errorf("Check(%q), got message %q, want %q", "dummy", res.Message, expected)
+		}
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -0,0 +1,205 @@
+package oci
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type oci struct {
+	name            string
+	scheme          string
+	host            string
+	path            string // for registries served under a relative URL
+	illegalPrefixes []string
+	illegalSuffixes []string
+	illegalPattern  *regexp.Regexp
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+// defaults holds the rules of the OCI distribution specification, which all
+// registries share. Unlike the checkers of other packages, it is not
+// registered, since no registry stands out as the default one.
+var defaults = oci{
+	illegalPrefixes: []string{"-", ".", "_"},
+	illegalSuffixes: []string{"-", ".", "_"},
+	// components are separated by ".", "_", "__" or runs of "-"
+	illegalPattern: regexp.MustCompile(`\.[-._]|_[-.]|___|-[._]`),
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 1,
+	maxLength: 255,
+	metadata: usrname.Metadata{
+		Category:  usrname.Packages,
+		Tags:      []string{"containers"},
+		RulesURL:  "https://github.com/opencontainers/distribution-spec/blob/main/spec.md#pulling-manifests",
+		Probeable: true,
+	},
+}
+
+// NewInstance returns a Checker, named name, for the registry at baseURL
+// (e.g. "https://registry.example.com").
+func NewInstance(name string, baseURL string) (usrname.Checker, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("oci: invalid base URL %q", baseURL)
+	}
+	c := defaults
+	c.name = name
+	c.scheme = u.Scheme
+	c.host = u.Host
+	c.path = strings.TrimSuffix(u.Path, "/")
+	c.metadata.Homepage = c.scheme + "://" + c.host + c.path
+	return &c, nil
+}
+
+// Register registers, under name, a Checker for the registry at baseURL and
+// returns it.
+func Register(name string, baseURL string) (usrname.Checker, error) {
+	c, err := NewInstance(name, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := usrname.Register(name, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *oci) Name() string {
+	return s.name
+}
+
+func (s *oci) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+// Registries have no web pages; the link designates the namespace as it
+// appears in image references.
+func (s *oci) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   s.path + "/" + username,
+	}
+	return u.String()
+}
+
+func (v *oci) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *oci) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *oci) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		IllegalSuffixes: append([]string(nil), v.illegalSuffixes...),
+		IllegalPattern:  v.illegalPattern.String(),
+	}
+}
+
+// Namespaces are lowercase.
+func (*oci) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://github.com/opencontainers/distribution-spec/blob/main/spec.md#pulling-manifests
+func (v *oci) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckIllegalSuffixes(v.illegalSuffixes),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *oci) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+
+		if res.StatusCode != http.StatusOK {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			return
+		}
+		var catalog struct {
+			Repositories []string `json:"repositories"`
+		}
+		if err := internal.DecodeJSON(res, &catalog); err != nil {
+			r.Status = usrname.UnknownStatus
+			r.Message = "unexpected response body"
+			return
+		}
+		r.Status = usrname.Available
+		for _, repo := range catalog.Repositories {
+			if strings.HasPrefix(repo, username+"/") {
+				r.Status = usrname.Unavailable
+			}
+		}
+		return
+	}
+}
+
+// request asks the catalog, which is sorted lexically, for the first
+// repository that follows username+"/"; the namespace is taken if and only
+// if that repository lies in it.
+func (c *oci) request(username string) *http.Request {
+	u := url.URL{
+		Scheme:   c.scheme,
+		Host:     c.host,
+		Path:     c.path + "/v2/_catalog",
+		RawQuery: url.Values{"n": {"1"}, "last": {username + "/"}}.Encode(),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
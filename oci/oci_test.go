@@ -0,0 +1,228 @@
+package oci_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/mockclient"
+	"github.com/jubobs/usrname/oci"
+)
+
+func newInstance(t *testing.T, baseURL string) usrname.Checker {
+	c, err := oci.NewInstance("test", baseURL)
+	if err != nil {
+		t.Fatalf("NewInstance, unexpected error %v", err)
+	}
+	return c
+}
+
+func TestNewInstance(t *testing.T) {
+	defer leaktest.Check(t)()
+	c := newInstance(t, "https://example.com/registry/")
+	const template = "got %q, want %q"
+	if actual, expected := c.Name(), "test"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if actual, expected := c.Link("foobar"), "https://example.com/registry/foobar"; actual != expected {
+		t.Errorf(template, actual, expected)
+	}
+	if _, err := oci.NewInstance("relative", "example.com"); err == nil {
+		t.Errorf("NewInstance with relative URL, got no error, want one")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer leaktest.Check(t)()
+	const name = "ACME registry"
+	c, err := oci.Register(name, "https://registry.example.com")
+	if err != nil {
+		t.Fatalf("Register(%q), unexpected error %v", name, err)
+	}
+	if actual, _ := usrname.CheckerFor(name); actual != c {
+		t.Errorf("CheckerFor(%q), got %v, want %v", name, actual, c)
+	}
+	if _, err := oci.Register(name, "https://other.example.com"); err == nil {
+		t.Errorf("Register twice, got no error, want one")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	checker := newInstance(t, "https://registry.example.com")
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"separators",
+			"foo.bar_baz__qux---quux",
+			noViolations,
+		}, {
+			"uppercase",
+			"fooBar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"periodprefix",
+			".bar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"mixedseparators",
+			"foo_-bar",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{3, 5},
+				},
+			},
+		}, {
+			"threeunderscores",
+			"foo___bar",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{3, 6},
+				},
+			},
+		}, {
+			"hyphensuffix",
+			"bar-",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: "-",
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	repos := []string{"free-ish/app", "taken/app", "taken/tool", "takenot/app"}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/_catalog" {
+			http.NotFound(w, r)
+			return
+		}
+		switch last := r.URL.Query().Get("last"); last {
+		case "broken/":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "garbled/":
+			fmt.Fprint(w, `<html>`)
+		default:
+			var next []string
+			for _, repo := range repos {
+				if repo > last {
+					next = append(next, repo)
+					break
+				}
+			}
+			fmt.Fprintf(w, `{"repositories":%q}`, next)
+		}
+	}))
+	defer ts.Close()
+	instance := newInstance(t, ts.URL)
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "free",
+			username: "free",
+			client:   usrname.NewClient(),
+			status:   usrname.Available,
+		}, {
+			label:    "last",
+			username: "zzz",
+			client:   usrname.NewClient(),
+			status:   usrname.Available,
+		}, {
+			label:    "taken",
+			username: "taken",
+			client:   usrname.NewClient(),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "garbled",
+			username: "garbled",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than 200
+			username: "broken",
+			client:   usrname.NewClient(),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := instance.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
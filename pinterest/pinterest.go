@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/jubobs/usrname"
@@ -19,6 +20,7 @@ type pinterest struct {
 	whitelist     *unicode.RangeTable
 	minLength     int
 	maxLength     int
+	metadata      usrname.Metadata
 }
 
 var pinterestImpl = pinterest{
@@ -36,6 +38,12 @@ var pinterestImpl = pinterest{
 	},
 	minLength: 3,
 	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"photos"},
+		Homepage:  "https://www.pinterest.com",
+		Probeable: false,
+	},
 }
 
 func init() {
@@ -52,6 +60,10 @@ func (s *pinterest) Name() string {
 	return s.name
 }
 
+func (s *pinterest) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
 func (*pinterest) Link(username string) string {
 	u := url.URL{
 		Scheme: pinterestImpl.scheme,
@@ -69,6 +81,20 @@ func (v *pinterest) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *pinterest) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: []string{v.illegalPrefix},
+	}
+}
+
+// Usernames are case-insensitive.
+func (*pinterest) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
 // See https://help.pinterest.com/en/managing-your-account/pinterest-username-rules
 func (v *pinterest) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
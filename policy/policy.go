@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/jubobs/usrname"
+)
+
+// Policy forbids usernames that contain any of a list of terms, such as
+// trademarks, protected names or profanity. Matching is case-insensitive.
+type Policy struct {
+	terms   []string
+	leet    bool
+	res     []*regexp.Regexp
+	pattern *regexp.Regexp
+}
+
+// leet lists the characters commonly substituted for each letter.
+var leet = map[rune]string{
+	'a': "4@",
+	'b': "8",
+	'e': "3",
+	'g': "69",
+	'i': "1!|",
+	'l': "1|",
+	'o': "0",
+	's': "5$",
+	't': "7+",
+	'z': "2",
+}
+
+func New(terms ...string) *Policy {
+	return newPolicy(terms, false)
+}
+
+// NewLeet is like New, but the resulting Policy also matches leetspeak
+// spellings of its terms; for instance, "tw1tt3r" matches "twitter".
+func NewLeet(terms ...string) *Policy {
+	return newPolicy(terms, true)
+}
+
+func newPolicy(terms []string, leet bool) *Policy {
+	p := Policy{leet: leet}
+	var exprs []string
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" {
+			continue
+		}
+		expr := expression(term, leet)
+		p.terms = append(p.terms, term)
+		p.res = append(p.res, regexp.MustCompile("(?i)"+expr))
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) != 0 {
+		p.pattern = regexp.MustCompile("(?i)" + strings.Join(exprs, "|"))
+	}
+	return &p
+}
+
+func expression(term string, leetAware bool) string {
+	if !leetAware {
+		return regexp.QuoteMeta(term)
+	}
+	var b bytes.Buffer
+	for _, r := range term {
+		subs, ok := leet[r]
+		if !ok {
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			continue
+		}
+		b.WriteByte('[')
+		b.WriteString(regexp.QuoteMeta(string(r)))
+		for _, s := range subs {
+			b.WriteString(regexp.QuoteMeta(string(s)))
+		}
+		b.WriteByte(']')
+	}
+	return b.String()
+}
+
+// ReadList reads terms from r, one per line. Blank lines and lines starting
+// with '#' are ignored.
+func ReadList(r io.Reader) ([]string, error) {
+	var terms []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		terms = append(terms, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (p *Policy) Terms() []string {
+	return append([]string(nil), p.terms...)
+}
+
+func (p *Policy) Leet() bool {
+	return p.leet
+}
+
+// Pattern returns a regular expression that matches any of the terms of p,
+// or nil if p has no terms.
+func (p *Policy) Pattern() *regexp.Regexp {
+	return p.pattern
+}
+
+// Validate reports each occurrence of a term of p in username as an
+// IllegalSubstring whose Pattern is the term and whose At holds the byte
+// offsets of the occurrence.
+func (p *Policy) Validate(username string) []usrname.Violation {
+	vv := []usrname.Violation{}
+	for i, re := range p.res {
+		for _, ii := range re.FindAllStringIndex(username, -1) {
+			v := usrname.IllegalSubstring{
+				Pattern: p.terms[i],
+				At:      ii,
+			}
+			vv = append(vv, &v)
+		}
+	}
+	return vv
+}
@@ -0,0 +1,123 @@
+package policy_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/policy"
+)
+
+func TestReadList(t *testing.T) {
+	defer leaktest.Check(t)()
+	const list = `# trademarks
+Twitter
+
+  GitHub
+# profanity
+darn
+`
+	expected := []string{"Twitter", "GitHub", "darn"}
+	actual, err := policy.ReadList(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("ReadList, unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ReadList, got %q, want %q", actual, expected)
+	}
+}
+
+func TestPattern(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		label    string
+		policy   *policy.Policy
+		expected string
+	}{
+		{"single", policy.New("Twitter"), "(?i)twitter"},
+		{"several", policy.New("twitter", "git.hub"), `(?i)twitter|git\.hub`},
+		{"leet", policy.NewLeet("bot"), `(?i)[b8][o0][t7\+]`},
+	}
+	const template = "Pattern(), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if actual := c.policy.Pattern().String(); actual != c.expected {
+				t.Errorf(template, actual, c.expected)
+			}
+		})
+	}
+	if p := policy.New(); p.Pattern() != nil {
+		t.Errorf("Pattern() of empty policy, got %v, want nil", p.Pattern())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		policy     *policy.Policy
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"clean",
+			policy.New("twitter"),
+			"jubobs",
+			noViolations,
+		}, {
+			"mixedcase",
+			policy.New("twitter"),
+			"not_ok_TwitteR",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: "twitter",
+					At:      []int{7, 14},
+				},
+			},
+		}, {
+			"leetignored",
+			policy.New("twitter"),
+			"tw1tt3r",
+			noViolations,
+		}, {
+			"leet",
+			policy.NewLeet("twitter"),
+			"real_Tw1tt3r",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: "twitter",
+					At:      []int{5, 12},
+				},
+			},
+		}, {
+			"severalterms",
+			policy.NewLeet("admin", "root"),
+			"r00t_4dmin_root",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: "admin",
+					At:      []int{5, 10},
+				},
+				&usrname.IllegalSubstring{
+					Pattern: "root",
+					At:      []int{0, 4},
+				},
+				&usrname.IllegalSubstring{
+					Pattern: "root",
+					At:      []int{11, 15},
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %#v, want %#v"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := c.policy.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package pypi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type pypi struct {
+	name            string
+	scheme          string
+	host            string
+	illegalPrefixes []string
+	illegalSuffixes []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	metadata        usrname.Metadata
+}
+
+var pypiImpl = pypi{
+	name:            "PyPI",
+	scheme:          "https",
+	host:            "pypi.org",
+	illegalPrefixes: []string{"-", ".", "_"},
+	illegalSuffixes: []string{"-", ".", "_"},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 1,
+	metadata: usrname.Metadata{
+		Category:  usrname.Packages,
+		Tags:      []string{"python"},
+		Homepage:  "https://pypi.org",
+		RulesURL:  "https://packaging.python.org/en/latest/specifications/name-normalization/",
+		Probeable: true,
+	},
+}
+
+// separators matches the runs of characters that PEP 503 deems equivalent.
+var separators = regexp.MustCompile(`[-_.]+`)
+
+func init() {
+	if err := usrname.Register(pypiImpl.name, &pypiImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &pypiImpl
+}
+
+func (s *pypi) Name() string {
+	return s.name
+}
+
+func (s *pypi) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *pypi) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/project/" + username + "/",
+	}
+	return u.String()
+}
+
+func (*pypi) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *pypi) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *pypi) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		IllegalSuffixes: append([]string(nil), v.illegalSuffixes...),
+	}
+}
+
+// Project names are normalized as per PEP 503: case and runs of separators
+// are insignificant.
+func (*pypi) Canonicalize(username string) string {
+	return separators.ReplaceAllString(strings.ToLower(username), "-")
+}
+
+// See https://packaging.python.org/en/latest/specifications/name-normalization/
+func (v *pypi) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckIllegalSuffixes(v.illegalSuffixes),
+	)
+}
+
+func (c *pypi) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+// request queries the JSON API under the normalized name, which it would
+// otherwise redirect to.
+func (c *pypi) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   "/pypi/" + c.Canonicalize(username) + "/json",
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
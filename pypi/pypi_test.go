@@ -0,0 +1,201 @@
+package pypi_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/mockclient"
+	"github.com/jubobs/usrname/pypi"
+)
+
+var checker = pypi.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "PyPI"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://pypi.org/project/" + username + "/"
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"empty",
+			"",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    1,
+					Actual: 0,
+				},
+			},
+		}, {
+			"onechar",
+			"x",
+			noViolations,
+		}, {
+			"specialchars",
+			"Foo.bar-baz__qux",
+			noViolations,
+		}, {
+			"exoticchars",
+			"exotic^chars",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{6},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"hyphenprefix",
+			"-bar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: "-",
+				},
+			},
+		}, {
+			"underscoresuffix",
+			"bar_",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: "_",
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		username  string
+		canonical string
+	}{
+		{"foobar", "foobar"},
+		{"Foo.Bar", "foo-bar"},
+		{"foo__bar", "foo-bar"},
+		{"Foo-._Bar", "foo-bar"},
+	}
+	const template = "Canonicalize(%q), got %q, want %q"
+	for _, c := range cases {
+		if actual := checker.Canonicalize(c.username); actual != c.canonical {
+			t.Errorf(template, c.username, actual, c.canonical)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "notfound",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusNotFound),
+			status:   usrname.Available,
+		}, {
+			label:    "ok",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusOK),
+			status:   usrname.Unavailable,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+		})
+	}
+}
+
+func TestCheckNormalizes(t *testing.T) {
+	defer leaktest.Check(t)()
+	var url string
+	client := clientFunc(func(req *http.Request) (*http.Response, error) {
+		url = req.URL.String()
+		return &http.Response{StatusCode: http.StatusNotFound}, nil
+	})
+	checker.Check(client)("Foo_Bar")
+	const expected = "https://pypi.org/pypi/foo-bar/json"
+	if url != expected {
+		template := "got request for %q, want %q"
+		t.Errorf(template, url, expected)
+	}
+}
+
+type clientFunc func(*http.Request) (*http.Response, error)
+
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/jubobs/usrname"
@@ -18,6 +19,7 @@ type reddit struct {
 	whitelist *unicode.RangeTable
 	minLength int
 	maxLength int
+	metadata  usrname.Metadata
 }
 
 var redditImpl = reddit{
@@ -34,6 +36,12 @@ var redditImpl = reddit{
 	},
 	minLength: 3,
 	maxLength: 20,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"community"},
+		Homepage:  "https://www.reddit.com",
+		Probeable: true,
+	},
 }
 
 func init() {
@@ -50,6 +58,10 @@ func (s *reddit) Name() string {
 	return s.name
 }
 
+func (s *reddit) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
 func (*reddit) Link(username string) string {
 	u := url.URL{
 		Scheme: redditImpl.scheme,
@@ -67,6 +79,19 @@ func (v *reddit) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *reddit) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Usernames are case-insensitive.
+func (*reddit) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
 // See https://help.reddit.com/en/managing-your-account/reddit-username-rules
 func (v *reddit) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
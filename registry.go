@@ -0,0 +1,329 @@
+package usrname
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Registry holds checkers and validators by name. Registries are safe for
+// concurrent use; the zero value is an empty Registry ready to use.
+type Registry struct {
+	mu         sync.RWMutex
+	checkers   map[string]Checker
+	validators map[string]Validator // checkers included
+	aliases    map[string]string    // lowercase alias to name
+}
+
+func NewRegistry() *Registry {
+	return new(Registry)
+}
+
+// lazyInit makes the maps of r, unless already made; callers must hold the
+// write lock. Reading from nil maps being fine, only writers need call it.
+func (r *Registry) lazyInit() {
+	if r.validators == nil {
+		r.checkers = make(map[string]Checker)
+		r.validators = make(map[string]Validator)
+		r.aliases = make(map[string]string)
+	}
+}
+
+// maxConcurrentChecks bounds the number of checks that Registry.Check runs
+// at a time, all of which share one client.
+const maxConcurrentChecks = 8
+
+// DefaultRegistry is the Registry used by the package-level functions, in
+// which site packages register their checkers.
+var DefaultRegistry = NewRegistry()
+
+// Register registers checker under name, both as a Checker and as a
+// Validator.
+func (r *Registry) Register(name string, checker Checker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if checker == nil {
+		return &NilCheckerError{Op: "Register", Kind: "checker", Name: name}
+	}
+	if r.taken(name) {
+		return &DuplicateError{Op: "Register", Kind: "checker", Name: name}
+	}
+	r.lazyInit()
+	r.checkers[name] = checker
+	r.validators[name] = checker
+	return nil
+}
+
+// RegisterValidator registers validator under name, for sites that are only
+// validated against their rules and never checked.
+func (r *Registry) RegisterValidator(name string, validator Validator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if validator == nil {
+		return &NilCheckerError{Op: "RegisterValidator", Kind: "validator", Name: name}
+	}
+	if r.taken(name) {
+		return &DuplicateError{Op: "RegisterValidator", Kind: "validator", Name: name}
+	}
+	r.lazyInit()
+	r.validators[name] = validator
+	return nil
+}
+
+// RegisterAlias registers alias (e.g. "gh") as another name for the checker
+// or validator registered under name, regardless of case, or under one of
+// its aliases. Like names, aliases are case-insensitive.
+func (r *Registry) RegisterAlias(alias string, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, exists := r.resolve(name)
+	if !exists {
+		return r.notFound("Validator", name, false)
+	}
+	if r.taken(alias) {
+		return &DuplicateError{Op: "RegisterAlias", Kind: "alias", Name: alias}
+	}
+	r.lazyInit()
+	r.aliases[strings.ToLower(alias)] = n
+	return nil
+}
+
+// notFound returns the error for name, which resolves to nothing.
+func (r *Registry) notFound(kind string, name string, checkersOnly bool) error {
+	return &NotFoundError{
+		Kind:        kind,
+		Name:        name,
+		Suggestions: r.suggest(name, checkersOnly),
+	}
+}
+
+// taken reports whether name, regardless of case, is already registered as
+// a name or as an alias.
+func (r *Registry) taken(name string) bool {
+	_, taken := r.resolve(name)
+	return taken
+}
+
+// resolve returns the name under which is registered what name designates:
+// name itself, regardless of case, or one of its aliases.
+func (r *Registry) resolve(name string) (string, bool) {
+	if _, exists := r.validators[name]; exists {
+		return name, true
+	}
+	for n := range r.validators {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	n, exists := r.aliases[strings.ToLower(name)]
+	return n, exists
+}
+
+// Unregister removes whatever checker or validator is registered under name,
+// along with its aliases.
+func (r *Registry) Unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, exists := r.resolve(name)
+	if !exists {
+		return r.notFound("Validator", name, false)
+	}
+	delete(r.checkers, n)
+	delete(r.validators, n)
+	for alias, target := range r.aliases {
+		if target == n {
+			delete(r.aliases, alias)
+		}
+	}
+	return nil
+}
+
+// Replace registers checker under name in place of whatever checker or
+// validator is registered under it.
+func (r *Registry) Replace(name string, checker Checker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if checker == nil {
+		return &NilCheckerError{Op: "Replace", Kind: "checker", Name: name}
+	}
+	n, exists := r.resolve(name)
+	if !exists {
+		return r.notFound("Validator", name, false)
+	}
+	r.checkers[n] = checker
+	r.validators[n] = checker
+	return nil
+}
+
+// ReplaceValidator registers validator under name in place of whatever
+// checker or validator is registered under it; a checker it replaces is no
+// longer checked.
+func (r *Registry) ReplaceValidator(name string, validator Validator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if validator == nil {
+		return &NilCheckerError{Op: "ReplaceValidator", Kind: "validator", Name: name}
+	}
+	n, exists := r.resolve(name)
+	if !exists {
+		return r.notFound("Validator", name, false)
+	}
+	delete(r.checkers, n)
+	r.validators[n] = validator
+	return nil
+}
+
+// Lookup returns the checker registered under name, regardless of case, or
+// under one of its aliases. If none is, it returns a *NotFoundError that
+// suggests names close to name.
+func (r *Registry) Lookup(name string) (Checker, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n, _ := r.resolve(name)
+	checker, exists := r.checkers[n]
+	if !exists {
+		return nil, r.notFound("Checker", name, true)
+	}
+	return checker, nil
+}
+
+// LookupValidator is like Lookup for validators, checkers included.
+func (r *Registry) LookupValidator(name string) (Validator, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n, _ := r.resolve(name)
+	validator, exists := r.validators[n]
+	if !exists {
+		return nil, r.notFound("Validator", name, false)
+	}
+	return validator, nil
+}
+
+// List returns the sorted names of the checkers of r that all filters keep,
+// e.g. List(InCategory(Code)).
+func (r *Registry) List(filters ...Filter) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []string
+	for name, checker := range r.checkers {
+		if keep(checker, filters) {
+			list = append(list, name)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// ListValidators returns the sorted names of the validators of r, checkers
+// included, that all filters keep.
+func (r *Registry) ListValidators(filters ...Filter) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []string
+	for name, validator := range r.validators {
+		if keep(validator, filters) {
+			list = append(list, name)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+func sortedKeys(m map[string]Checker) []string {
+	var list []string
+	for name := range m {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Scope returns a new Registry that holds the checkers and validators of r
+// registered under names, e.g. those that a tenant allows. It fails if any
+// of names is unknown to r.
+func (r *Registry) Scope(names ...string) (*Registry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s := NewRegistry()
+	s.lazyInit()
+	for _, name := range names {
+		n, exists := r.resolve(name)
+		if !exists {
+			return nil, r.notFound("Validator", name, false)
+		}
+		s.validators[n] = r.validators[n]
+		if checker, ok := r.checkers[n]; ok {
+			s.checkers[n] = checker
+		}
+	}
+	for alias, n := range r.aliases {
+		if _, ok := s.validators[n]; ok {
+			s.aliases[alias] = n
+		}
+	}
+	return s, nil
+}
+
+// Check checks username, concurrently, on every checker of r through client
+// and returns the results in the order of the names of the checkers. Since
+// they all share client, at most maxConcurrentChecks of them run at a time.
+func (r *Registry) Check(client Client, username string) []Result {
+	r.mu.RLock()
+	names := sortedKeys(r.checkers)
+	checkers := make([]Checker, len(names))
+	for i, name := range names {
+		checkers[i] = r.checkers[name]
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checkers))
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = checker.Check(client)(username)
+		}(i, checker)
+	}
+	wg.Wait()
+	return results
+}
+
+// Register registers checker under name in DefaultRegistry.
+func Register(name string, checker Checker) error {
+	return DefaultRegistry.Register(name, checker)
+}
+
+// RegisterValidator registers validator under name in DefaultRegistry.
+func RegisterValidator(name string, validator Validator) error {
+	return DefaultRegistry.RegisterValidator(name, validator)
+}
+
+// RegisterAlias registers alias as another name for name in DefaultRegistry.
+func RegisterAlias(alias string, name string) error {
+	return DefaultRegistry.RegisterAlias(alias, name)
+}
+
+func Checkers(filters ...Filter) []string {
+	return DefaultRegistry.List(filters...)
+}
+
+func CheckerFor(name string) (Checker, error) {
+	return DefaultRegistry.Lookup(name)
+}
+
+func Validators(filters ...Filter) []string {
+	return DefaultRegistry.ListValidators(filters...)
+}
+
+func ValidatorFor(name string) (Validator, error) {
+	return DefaultRegistry.LookupValidator(name)
+}
+
+// Check checks username on every checker of DefaultRegistry.
+func Check(client Client, username string) []Result {
+	return DefaultRegistry.Check(client, username)
+}
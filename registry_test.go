@@ -0,0 +1,354 @@
+package usrname_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/custom"
+)
+
+// stub is a Checker whose checks all end with the same status.
+type stub struct {
+	usrname.Validator
+	status usrname.Status
+}
+
+func newStub(t *testing.T, name string, status usrname.Status) *stub {
+	v, err := custom.New(custom.Options{Name: name})
+	if err != nil {
+		t.Fatalf("custom.New, unexpected error %v", err)
+	}
+	return &stub{v, status}
+}
+
+func (s *stub) Check(usrname.Client) func(string) usrname.Result {
+	return func(username string) usrname.Result {
+		return usrname.Result{Username: username, Checker: s, Status: s.status}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	defer leaktest.Check(t)()
+	r := usrname.NewRegistry()
+	a := newStub(t, "A", usrname.Available)
+	b := newStub(t, "B", usrname.Unavailable)
+	v, _ := custom.New(custom.Options{Name: "V"})
+	for name, c := range map[string]usrname.Checker{"A": a, "B": b} {
+		if err := r.Register(name, c); err != nil {
+			t.Fatalf("Register(%q), unexpected error %v", name, err)
+		}
+	}
+	if err := r.RegisterValidator("V", v); err != nil {
+		t.Fatalf("RegisterValidator(%q), unexpected error %v", "V", err)
+	}
+	if err := r.Register("V", a); err == nil {
+		t.Errorf("Register(%q), got no error, want one", "V")
+	}
+	if actual, expected := r.List(), []string{"A", "B"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("List(), got %q, want %q", actual, expected)
+	}
+	if actual, expected := r.ListValidators(), []string{"A", "B", "V"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ListValidators(), got %q, want %q", actual, expected)
+	}
+	if _, err := usrname.CheckerFor("A"); err == nil {
+		t.Errorf("CheckerFor(%q) in default registry, got no error, want one", "A")
+	}
+
+	if err := r.Replace("A", b); err != nil {
+		t.Fatalf("Replace(%q), unexpected error %v", "A", err)
+	}
+	if actual, _ := r.Lookup("A"); actual != b {
+		t.Errorf("Lookup(%q), got %v, want %v", "A", actual, b)
+	}
+	if err := r.Replace("C", b); err == nil {
+		t.Errorf("Replace(%q), got no error, want one", "C")
+	}
+	if err := r.Replace("A", nil); err == nil {
+		t.Errorf("Replace(nil), got no error, want one")
+	}
+	if err := r.Replace("v", a); err != nil {
+		t.Fatalf("Replace(%q), unexpected error %v", "v", err)
+	}
+	if actual, _ := r.Lookup("V"); actual != a {
+		t.Errorf("Lookup(%q) after Replace, got %v, want %v", "V", actual, a)
+	}
+	if err := r.ReplaceValidator("V", v); err != nil {
+		t.Fatalf("ReplaceValidator(%q), unexpected error %v", "V", err)
+	}
+	if _, err := r.Lookup("V"); err == nil {
+		t.Errorf("Lookup(%q) after ReplaceValidator, got no error, want one", "V")
+	}
+	if actual, _ := r.LookupValidator("V"); actual != v {
+		t.Errorf("LookupValidator(%q), got %v, want %v", "V", actual, v)
+	}
+	if err := r.ReplaceValidator("C", v); err == nil {
+		t.Errorf("ReplaceValidator(%q), got no error, want one", "C")
+	}
+	if err := r.ReplaceValidator("V", nil); err == nil {
+		t.Errorf("ReplaceValidator(nil), got no error, want one")
+	}
+
+	if err := r.Unregister("B"); err != nil {
+		t.Fatalf("Unregister(%q), unexpected error %v", "B", err)
+	}
+	if _, err := r.Lookup("B"); err == nil {
+		t.Errorf("Lookup(%q), got no error, want one", "B")
+	}
+	if _, err := r.LookupValidator("B"); err == nil {
+		t.Errorf("LookupValidator(%q), got no error, want one", "B")
+	}
+	if err := r.Unregister("B"); err == nil {
+		t.Errorf("Unregister twice, got no error, want one")
+	}
+	if err := r.Register("B", a); err != nil {
+		t.Errorf("Register after Unregister, unexpected error %v", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"GitHub", "GitHub"},
+		{"github", "GitHub"},
+		{"gh", "GitHub"},
+		{"GH", "GitHub"},
+		{"x", "Twitter"},
+		{"ig", "Instagram"},
+		{"fb", "facebook"},
+		{"Facebook", "facebook"},
+		{"stack overflow", "Stack Overflow"},
+	}
+	const template = "CheckerFor(%q), got %v, want %q"
+	for _, c := range cases {
+		checker, err := usrname.CheckerFor(c.name)
+		if err != nil || checker.Name() != c.expected {
+			t.Errorf(template, c.name, checker, c.expected)
+		}
+	}
+}
+
+func TestLookupSuggestions(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"gthub", "usrname: Checker not found for gthub (did you mean GitHub?)"},
+		{"gtihub", "usrname: Checker not found for gtihub (did you mean GitHub?)"}, // transposition
+		{"twiter", "usrname: Checker not found for twiter (did you mean Twitter or Twitch?)"},
+		{"xx", "usrname: Checker not found for xx (did you mean Twitter?)"},
+		{"nonexistent", "usrname: Checker not found for nonexistent"},
+	}
+	const template = "CheckerFor(%q), got error %q, want %q"
+	for _, c := range cases {
+		_, err := usrname.CheckerFor(c.name)
+		if err == nil || err.Error() != c.expected {
+			t.Errorf(template, c.name, err, c.expected)
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	defer leaktest.Check(t)()
+	r := usrname.NewRegistry()
+	a := newStub(t, "Alpha", usrname.Available)
+	if err := r.Register("Alpha", a); err != nil {
+		t.Fatalf("Register, unexpected error %v", err)
+	}
+	if err := r.Register("alpha", a); err == nil {
+		t.Errorf("Register(%q), got no error, want one", "alpha")
+	}
+	if err := r.RegisterAlias("a", "Alpha"); err != nil {
+		t.Fatalf("RegisterAlias, unexpected error %v", err)
+	}
+	if err := r.RegisterAlias("A", "Alpha"); err == nil {
+		t.Errorf("RegisterAlias(%q) twice, got no error, want one", "A")
+	}
+	if err := r.RegisterAlias("al", "ALPHA"); err != nil {
+		t.Errorf("RegisterAlias for %q, unexpected error %v", "ALPHA", err)
+	}
+	if actual, _ := r.Lookup("AL"); actual != a {
+		t.Errorf("Lookup(%q), got %v, want %v", "AL", actual, a)
+	}
+	if err := r.RegisterAlias("b", "Beta"); err == nil {
+		t.Errorf("RegisterAlias for unknown name, got no error, want one")
+	}
+	if err := r.Register("A", a); err == nil {
+		t.Errorf("Register(%q) over alias, got no error, want one", "A")
+	}
+	if err := r.Unregister("a"); err != nil {
+		t.Fatalf("Unregister(%q), unexpected error %v", "a", err)
+	}
+	if _, err := r.Lookup("Alpha"); err == nil {
+		t.Errorf("Lookup(%q) after Unregister, got no error, want one", "Alpha")
+	}
+	if err := r.Register("a", a); err != nil {
+		t.Errorf("Register(%q) after Unregister, unexpected error %v", "a", err)
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	defer leaktest.Check(t)()
+	r := usrname.NewRegistry()
+	a := newStub(t, "Alpha", usrname.Available)
+	if err := r.Register("Alpha", a); err != nil {
+		t.Fatalf("Register, unexpected error %v", err)
+	}
+
+	_, err := r.Lookup("alpah")
+	nf, ok := err.(*usrname.NotFoundError)
+	if !ok || !nf.Is(usrname.ErrNotFound) {
+		t.Fatalf("Lookup, got error %v, want a *NotFoundError", err)
+	}
+	if actual, expected := nf.Name, "alpah"; actual != expected {
+		t.Errorf("NotFoundError.Name, got %q, want %q", actual, expected)
+	}
+	if actual, expected := nf.Suggestions, []string{"Alpha"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("NotFoundError.Suggestions, got %q, want %q", actual, expected)
+	}
+	err = r.Unregister("Beta")
+	if _, ok := err.(*usrname.NotFoundError); !ok {
+		t.Errorf("Unregister, got error %v, want a *NotFoundError", err)
+	}
+
+	err = r.Register("ALPHA", a)
+	dup, ok := err.(*usrname.DuplicateError)
+	if !ok || !dup.Is(usrname.ErrDuplicate) {
+		t.Fatalf("Register twice, got error %v, want a *DuplicateError", err)
+	}
+	if actual, expected := dup.Name, "ALPHA"; actual != expected {
+		t.Errorf("DuplicateError.Name, got %q, want %q", actual, expected)
+	}
+	if dup.Is(usrname.ErrNotFound) {
+		t.Errorf("Register twice, got error matching ErrNotFound")
+	}
+
+	err = r.Register("Beta", nil)
+	nilErr, ok := err.(*usrname.NilCheckerError)
+	if !ok || !nilErr.Is(usrname.ErrNilChecker) {
+		t.Fatalf("Register(nil), got error %v, want a *NilCheckerError", err)
+	}
+	if actual, expected := nilErr.Op, "Register"; actual != expected {
+		t.Errorf("NilCheckerError.Op, got %q, want %q", actual, expected)
+	}
+	err = r.RegisterValidator("Beta", nil)
+	if _, ok := err.(*usrname.NilCheckerError); !ok {
+		t.Errorf("RegisterValidator(nil), got error %v, want a *NilCheckerError", err)
+	}
+	err = r.Replace("Alpha", nil)
+	if _, ok := err.(*usrname.NilCheckerError); !ok {
+		t.Errorf("Replace(nil), got error %v, want a *NilCheckerError", err)
+	}
+}
+
+func TestScope(t *testing.T) {
+	defer leaktest.Check(t)()
+	tenant, err := usrname.DefaultRegistry.Scope("GitHub", "GitLab")
+	if err != nil {
+		t.Fatalf("Scope, unexpected error %v", err)
+	}
+	if actual, expected := tenant.List(), []string{"GitHub", "GitLab"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("List(), got %q, want %q", actual, expected)
+	}
+	if err := tenant.Unregister("GitLab"); err != nil {
+		t.Fatalf("Unregister, unexpected error %v", err)
+	}
+	if _, err := usrname.CheckerFor("GitLab"); err != nil {
+		t.Errorf("CheckerFor(%q) after scoped Unregister, unexpected error %v", "GitLab", err)
+	}
+	if _, err := usrname.DefaultRegistry.Scope("GitHub", "nonexistent"); err == nil {
+		t.Errorf("Scope with unknown name, got no error, want one")
+	}
+}
+
+func TestRegistryCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+	r := usrname.NewRegistry()
+	r.Register("B", newStub(t, "B", usrname.Unavailable))
+	r.Register("A", newStub(t, "A", usrname.Available))
+	r.RegisterValidator("V", newStub(t, "V", usrname.Invalid))
+	results := r.Check(nil, "foobar")
+	if len(results) != 2 {
+		t.Fatalf("Check, got %d results, want 2", len(results))
+	}
+	for i, expected := range []usrname.Status{usrname.Available, usrname.Unavailable} {
+		if actual := results[i].Status; actual != expected {
+			t.Errorf("Check, result %d, got %q, want %q", i, actual, expected)
+		}
+		if actual := results[i].Username; actual != "foobar" {
+			t.Errorf("Check, result %d, got username %q, want %q", i, actual, "foobar")
+		}
+	}
+}
+
+func TestRegistryZeroValue(t *testing.T) {
+	defer leaktest.Check(t)()
+	var r usrname.Registry
+	if actual := r.List(); len(actual) != 0 {
+		t.Errorf("List(), got %q, want none", actual)
+	}
+	if _, err := r.Lookup("A"); err == nil {
+		t.Errorf("Lookup(%q), got no error, want one", "A")
+	}
+	a := newStub(t, "A", usrname.Available)
+	if err := r.Register("A", a); err != nil {
+		t.Fatalf("Register(%q), unexpected error %v", "A", err)
+	}
+	if err := r.RegisterAlias("a1", "A"); err != nil {
+		t.Fatalf("RegisterAlias(%q), unexpected error %v", "a1", err)
+	}
+	if actual, _ := r.Lookup("A1"); actual != a {
+		t.Errorf("Lookup(%q), got %v, want %v", "A1", actual, a)
+	}
+}
+
+// gauge is a Checker whose checks take a while and that records how many of
+// them run at once.
+type gauge struct {
+	*stub
+	mu      *sync.Mutex
+	running *int
+	max     *int
+}
+
+func (g *gauge) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) usrname.Result {
+		g.mu.Lock()
+		*g.running++
+		if *g.running > *g.max {
+			*g.max = *g.running
+		}
+		g.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		g.mu.Lock()
+		*g.running--
+		g.mu.Unlock()
+		return g.stub.Check(client)(username)
+	}
+}
+
+func TestRegistryCheckConcurrency(t *testing.T) {
+	defer leaktest.Check(t)()
+	const n, limit = 50, 8
+	var mu sync.Mutex
+	var running, max int
+	r := usrname.NewRegistry()
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("C%02d", i)
+		r.Register(name, &gauge{newStub(t, name, usrname.Available), &mu, &running, &max})
+	}
+	if results := r.Check(nil, "foobar"); len(results) != n {
+		t.Fatalf("Check, got %d results, want %d", len(results), n)
+	}
+	if max > limit {
+		t.Errorf("Check, got %d checks at once, want at most %d", max, limit)
+	}
+}
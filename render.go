@@ -0,0 +1,155 @@
+package usrname
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	ansiHighlight = "\x1b[1;4;31m" // bold, underlined, red
+	ansiGap       = "\x1b[7m \x1b[0m"
+	ansiReset     = "\x1b[0m"
+)
+
+// Carets renders username followed by one line per violation, with carets
+// under the characters that the violation points at, for display in a
+// terminal with a monospaced font. It fails if any of vv does not fit
+// username.
+func Carets(username string, vv []Violation) (string, error) {
+	gg, widths := graphemes(username)
+	width := 1 // room for a caret past the end
+	for _, w := range widths {
+		width += w
+	}
+	var b bytes.Buffer
+	for _, g := range gg {
+		b.WriteString(printable(g))
+	}
+	b.WriteByte('\n')
+	for _, v := range vv {
+		marked := make([]bool, len(gg)+1)
+		ss, err := Spans(username, v)
+		if err != nil {
+			return "", err
+		}
+		for _, s := range ss {
+			if s.Graphemes[0] == s.Graphemes[1] {
+				marked[s.Graphemes[0]] = true
+			}
+			for g := s.Graphemes[0]; g < s.Graphemes[1]; g++ {
+				marked[g] = true
+			}
+		}
+		var line bytes.Buffer
+		for g, m := range marked {
+			w := 1
+			if g < len(widths) {
+				w = widths[g]
+			}
+			mark := " "
+			if m {
+				mark = "^"
+			}
+			line.WriteString(strings.Repeat(mark, w))
+		}
+		fmt.Fprintf(&b, "%-*s %v\n", width, line.String(), v)
+	}
+	return b.String(), nil
+}
+
+// Highlight renders username with the characters that vv point at
+// highlighted by ANSI escape sequences. Positions between characters, such as
+// the end of a username that is too short, are shown as a reverse-video space.
+// It fails if any of vv does not fit username.
+func Highlight(username string, vv []Violation) (string, error) {
+	gg, _ := graphemes(username)
+	marked := make([]bool, len(gg))
+	gaps := make([]bool, len(gg)+1)
+	for _, v := range vv {
+		ss, err := Spans(username, v)
+		if err != nil {
+			return "", err
+		}
+		for _, s := range ss {
+			if s.Graphemes[0] == s.Graphemes[1] {
+				gaps[s.Graphemes[0]] = true
+			}
+			for g := s.Graphemes[0]; g < s.Graphemes[1]; g++ {
+				marked[g] = true
+			}
+		}
+	}
+	var b bytes.Buffer
+	on := false
+	for g := 0; g <= len(gg); g++ {
+		if gaps[g] {
+			if on {
+				b.WriteString(ansiReset)
+				on = false
+			}
+			b.WriteString(ansiGap)
+		}
+		if g == len(gg) {
+			break
+		}
+		if marked[g] != on {
+			if on {
+				b.WriteString(ansiReset)
+			} else {
+				b.WriteString(ansiHighlight)
+			}
+			on = marked[g]
+		}
+		b.WriteString(printable(gg[g]))
+	}
+	if on {
+		b.WriteString(ansiReset)
+	}
+	return b.String(), nil
+}
+
+// graphemes splits s into grapheme clusters and returns them along with the
+// number of terminal columns that each of them occupies.
+func graphemes(s string) ([]string, []int) {
+	rr := []rune(s)
+	bounds := graphemeBounds(s)
+	gg := make([]string, len(bounds))
+	widths := make([]int, len(bounds))
+	for i, start := range bounds {
+		end := len(rr)
+		if i+1 < len(bounds) {
+			end = bounds[i+1]
+		}
+		gg[i] = string(rr[start:end])
+		widths[i] = 1
+		if isWide(rr[start]) {
+			widths[i] = 2
+		}
+	}
+	return gg, widths
+}
+
+// printable replaces control characters, which would mess up the layout.
+func printable(g string) string {
+	if r := []rune(g)[0]; isControl(r) || unicode.IsSpace(r) {
+		return "�"
+	}
+	return g
+}
+
+// isWide approximates the East Asian Wide and Fullwidth characters of Unicode
+// Standard Annex #11, along with emoji, which terminals show in two columns.
+func isWide(r rune) bool {
+	return 0x1100 <= r && r <= 0x115f ||
+		0x2e80 <= r && r <= 0xa4cf && r != 0x303f ||
+		0xac00 <= r && r <= 0xd7a3 ||
+		0xf900 <= r && r <= 0xfaff ||
+		0xfe30 <= r && r <= 0xfe4f ||
+		0xff00 <= r && r <= 0xff60 ||
+		0xffe0 <= r && r <= 0xffe6 ||
+		0x1f300 <= r && r <= 0x1f64f ||
+		0x1f900 <= r && r <= 0x1f9ff ||
+		0x20000 <= r && r <= 0x3fffd
+}
@@ -0,0 +1,169 @@
+package rubygems
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type rubygems struct {
+	name            string
+	scheme          string
+	host            string
+	illegalPrefixes []string
+	illegalPattern  *regexp.Regexp
+	reserved        []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	metadata        usrname.Metadata
+}
+
+var rubygemsImpl = rubygems{
+	name:            "RubyGems",
+	scheme:          "https",
+	host:            "rubygems.org",
+	illegalPrefixes: []string{"-", ".", "_"},
+	illegalPattern:  regexp.MustCompile(`^[-._0-9]+$`), // no letters
+	reserved: []string{
+		"cgi-bin", "gem", "install", "jruby", "mri", "ruby", "rubygems",
+		"uninstall", "update_rubygems",
+	},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 1,
+	metadata: usrname.Metadata{
+		Category:  usrname.Packages,
+		Tags:      []string{"ruby"},
+		Homepage:  "https://rubygems.org",
+		RulesURL:  "https://guides.rubygems.org/name-your-gem/",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(rubygemsImpl.name, &rubygemsImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &rubygemsImpl
+}
+
+func (s *rubygems) Name() string {
+	return s.name
+}
+
+func (s *rubygems) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *rubygems) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/gems/" + username,
+	}
+	return u.String()
+}
+
+func (v *rubygems) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *rubygems) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *rubygems) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		IllegalPattern:  v.illegalPattern.String(),
+		Reserved:        append([]string(nil), v.reserved...),
+	}
+}
+
+// Gem names are case-sensitive, but must be unique regardless of case.
+func (*rubygems) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://guides.rubygems.org/name-your-gem/
+func (v *rubygems) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckNotReserved(v.reserved),
+	)
+}
+
+func (c *rubygems) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			// The API looks gems up by their exact spelling only, and gem
+			// names always contain letters.
+			r.Status = usrname.UnknownStatus
+			const templ = "%q is free on %s, but names that differ from it only in case may not be"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+func (c *rubygems) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.host,
+		Path:   "/api/v1/gems/" + username + ".json",
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,344 @@
+package usrname
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"unicode"
+
+	"github.com/jubobs/usrname/internal/dfa"
+)
+
+// Rules describes the constraints that a Validator enforces, in a form that
+// can be serialized (e.g. to JSON) and mirrored by other systems. In JSON,
+// the whitelist is a list of pairs of inclusive bounds, as code points (e.g.
+// [[48,57],[97,122]] for 0-9 and a-z).
+type Rules struct {
+	MinLength         int                 `json:"minLength"`
+	MaxLength         int                 `json:"maxLength,omitempty"`
+	Whitelist         *unicode.RangeTable `json:"-"`
+	IllegalPrefixes   []string            `json:"illegalPrefixes,omitempty"`
+	IllegalSuffixes   []string            `json:"illegalSuffixes,omitempty"`
+	IllegalSubstrings []string            `json:"illegalSubstrings,omitempty"`
+	IllegalPattern    string              `json:"illegalPattern,omitempty"`
+	Reserved          []string            `json:"reserved,omitempty"` // regardless of case
+	// IDNA requires usernames to be valid IDNA labels (RFC 5891), as for
+	// domain names: A-labels must decode into valid U-labels, and U-labels
+	// must fit in 63 octets once encoded. No regular expression can check
+	// as much, so Regexp and HTMLPattern fail for such rules.
+	IDNA bool `json:"idna,omitempty"`
+}
+
+// errIDNA is the cause of the errors that Regexp and HTMLPattern return for
+// rules that require IDNA labels.
+var errIDNA = errors.New("IDNA labels cannot be checked by a regular expression")
+
+// jsonRules is the JSON form of Rules.
+type jsonRules struct {
+	rules
+	Whitelist [][2]rune `json:"whitelist,omitempty"`
+}
+
+// rules has the fields of Rules but not its methods.
+type rules Rules
+
+func (r Rules) MarshalJSON() ([]byte, error) {
+	j := jsonRules{rules: rules(r)}
+	if r.Whitelist != nil {
+		rr := rangesOf(r.Whitelist)
+		j.Whitelist = make([][2]rune, 0, len(rr)/2)
+		for i := 0; i < len(rr); i += 2 {
+			j.Whitelist = append(j.Whitelist, [2]rune{rr[i], rr[i+1]})
+		}
+	}
+	return json.Marshal(j)
+}
+
+func (r *Rules) UnmarshalJSON(b []byte) error {
+	var j jsonRules
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*r = Rules(j.rules)
+	if j.Whitelist != nil {
+		t := unicode.RangeTable{}
+		for _, p := range j.Whitelist {
+			lo, hi := p[0], p[1]
+			if lo < 0 || hi < lo || hi > unicode.MaxRune {
+				return fmt.Errorf("usrname: invalid whitelist range [%d, %d]", lo, hi)
+			}
+			if hi <= 0xFFFF {
+				t.R16 = append(t.R16, unicode.Range16{Lo: uint16(lo), Hi: uint16(hi), Stride: 1})
+			} else {
+				t.R32 = append(t.R32, unicode.Range32{Lo: uint32(lo), Hi: uint32(hi), Stride: 1})
+			}
+		}
+		r.Whitelist = &t
+	}
+	return nil
+}
+
+// JSONSchema returns a JSON Schema describing the strings that satisfy r.
+func (r *Rules) JSONSchema() (map[string]interface{}, error) {
+	pattern, err := r.HTMLPattern()
+	if err != nil {
+		return nil, err
+	}
+	schema := map[string]interface{}{
+		"type":      "string",
+		"minLength": r.MinLength,
+		"pattern":   "^(?:" + pattern + ")$",
+	}
+	if r.MaxLength != 0 {
+		schema["maxLength"] = r.MaxLength
+	}
+	return schema, nil
+}
+
+// HTMLPattern returns an ECMAScript regular expression, suitable for the
+// pattern attribute of an HTML input element, that matches exactly the
+// strings that satisfy r. As the pattern attribute requires, the expression
+// is meant to be matched against the whole input and in Unicode mode.
+func (r *Rules) HTMLPattern() (string, error) {
+	if r.IDNA {
+		return "", &UnsupportedRulesError{errIDNA}
+	}
+	var b bytes.Buffer
+	for _, p := range r.IllegalPrefixes {
+		fmt.Fprintf(&b, "(?!%s)", jsLiteral(p))
+	}
+	for _, s := range r.IllegalSubstrings {
+		fmt.Fprintf(&b, `(?![\s\S]*%s)`, jsLiteral(s))
+	}
+	for _, s := range r.IllegalSuffixes {
+		fmt.Fprintf(&b, `(?![\s\S]*%s$)`, jsLiteral(s))
+	}
+	if len(r.Reserved) != 0 {
+		b.WriteString("(?!(?:")
+		for i, w := range r.Reserved {
+			if i != 0 {
+				b.WriteString("|")
+			}
+			for _, c := range w {
+				b.WriteString(jsClass(foldRanges(c), false))
+			}
+		}
+		b.WriteString(")$)")
+	}
+	if r.IllegalPattern != "" {
+		re, err := syntax.Parse(r.IllegalPattern, syntax.Perl)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(`(?![\s\S]*(?:`)
+		if err := writeJS(&b, re); err != nil {
+			return "", err
+		}
+		b.WriteString("))")
+	}
+	if r.Whitelist != nil {
+		b.WriteString(jsClass(rangesOf(r.Whitelist), false))
+	} else {
+		b.WriteString(`[\s\S]`)
+	}
+	if r.MaxLength != 0 {
+		fmt.Fprintf(&b, "{%d,%d}", r.MinLength, r.MaxLength)
+	} else {
+		fmt.Fprintf(&b, "{%d,}", r.MinLength)
+	}
+	return b.String(), nil
+}
+
+// Regexp returns a regular expression that matches exactly the strings that
+// satisfy r. Unlike HTMLPattern, it only relies on RE2 features and can
+// therefore be used wherever RE2 (or a subset of PCRE) is expected.
+//
+// Regexp supports a subset of rules, and fails with an *UnsupportedRulesError
+// outside of it. IllegalPattern may only use ^ and $ (as text anchors) on top
+// of the constructs of regular languages: not \b, \B, nor multi-line mode.
+// Moreover, the expression grows with MaxLength and with the number of states
+// needed to tell the other rules apart, and Regexp gives up once it would be
+// unreasonably large. Reserved lists, length limits on parts of usernames and
+// bans on runs of special characters each fit on their own, but some of them
+// together with a MaxLength in the hundreds do not, as for GitLab, Codeberg and
+// Bluesky. Neither can it check IDNA labels.
+func (r *Rules) Regexp() (*regexp.Regexp, error) {
+	if r.IDNA {
+		return nil, &UnsupportedRulesError{errIDNA}
+	}
+	spec := dfa.Spec{
+		Prefixes:   r.IllegalPrefixes,
+		Suffixes:   r.IllegalSuffixes,
+		Substrings: r.IllegalSubstrings,
+		Reserved:   r.Reserved,
+		MinLength:  r.MinLength,
+		MaxLength:  r.MaxLength,
+	}
+	if r.Whitelist != nil {
+		spec.Whitelist = rangesOf(r.Whitelist)
+	}
+	if r.IllegalPattern != "" {
+		re, err := syntax.Parse(r.IllegalPattern, syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		spec.Pattern = re
+	}
+	expr, err := dfa.Compile(spec)
+	if err != nil {
+		return nil, &UnsupportedRulesError{err}
+	}
+	return regexp.Compile(`^(?:` + expr + `)$`)
+}
+
+// rangesOf flattens t into pairs of inclusive bounds, as used by
+// regexp/syntax.
+func rangesOf(t *unicode.RangeTable) []rune {
+	var rr []rune
+	add := func(lo, hi, stride uint32) {
+		if stride == 1 {
+			rr = append(rr, rune(lo), rune(hi))
+			return
+		}
+		for r := lo; r <= hi; r += stride {
+			rr = append(rr, rune(r), rune(r))
+		}
+	}
+	for _, r := range t.R16 {
+		add(uint32(r.Lo), uint32(r.Hi), uint32(r.Stride))
+	}
+	for _, r := range t.R32 {
+		add(r.Lo, r.Hi, r.Stride)
+	}
+	return rr
+}
+
+func jsLiteral(s string) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		b.WriteString(jsRune(r, false))
+	}
+	return b.String()
+}
+
+func jsClass(rr []rune, negated bool) string {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	if negated {
+		b.WriteByte('^')
+	}
+	for i := 0; i+1 < len(rr); i += 2 {
+		b.WriteString(jsRune(rr[i], true))
+		if rr[i] != rr[i+1] {
+			b.WriteByte('-')
+			b.WriteString(jsRune(rr[i+1], true))
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// jsRune escapes r so that it stands for itself in an ECMAScript regular
+// expression in Unicode mode, either inside or outside a character class.
+func jsRune(r rune, inClass bool) string {
+	switch {
+	case r < ' ' || r > '~':
+		return fmt.Sprintf(`\u{%x}`, r)
+	case bytes.ContainsRune([]byte(`^$\.*+?()[]{}|/`), r):
+		return `\` + string(r)
+	case r == '-' && inClass:
+		return `\-`
+	}
+	return string(r)
+}
+
+func writeJS(b *bytes.Buffer, re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		b.WriteString("[]")
+	case syntax.OpEmptyMatch:
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 {
+				b.WriteString(jsClass(foldRanges(r), false))
+			} else {
+				b.WriteString(jsRune(r, false))
+			}
+		}
+	case syntax.OpCharClass:
+		b.WriteString(jsClass(re.Rune, false))
+	case syntax.OpAnyCharNotNL:
+		b.WriteString(`[^\n]`)
+	case syntax.OpAnyChar:
+		b.WriteString(`[\s\S]`)
+	case syntax.OpBeginText:
+		b.WriteString("^")
+	case syntax.OpEndText:
+		b.WriteString("$")
+	case syntax.OpWordBoundary:
+		b.WriteString(`\b`)
+	case syntax.OpNoWordBoundary:
+		b.WriteString(`\B`)
+	case syntax.OpCapture:
+		b.WriteString("(?:")
+		if err := writeJS(b, re.Sub[0]); err != nil {
+			return err
+		}
+		b.WriteString(")")
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		b.WriteString("(?:")
+		if err := writeJS(b, re.Sub[0]); err != nil {
+			return err
+		}
+		b.WriteString(")")
+		switch re.Op {
+		case syntax.OpStar:
+			b.WriteString("*")
+		case syntax.OpPlus:
+			b.WriteString("+")
+		case syntax.OpQuest:
+			b.WriteString("?")
+		default:
+			if re.Max == -1 {
+				fmt.Fprintf(b, "{%d,}", re.Min)
+			} else {
+				fmt.Fprintf(b, "{%d,%d}", re.Min, re.Max)
+			}
+		}
+		if re.Flags&syntax.NonGreedy != 0 {
+			b.WriteString("?")
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := writeJS(b, sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		b.WriteString("(?:")
+		for i, sub := range re.Sub {
+			if i != 0 {
+				b.WriteString("|")
+			}
+			if err := writeJS(b, sub); err != nil {
+				return err
+			}
+		}
+		b.WriteString(")")
+	default:
+		return fmt.Errorf("usrname: unsupported regular expression %s", re)
+	}
+	return nil
+}
+
+// foldRanges returns the ranges of the runes that r matches case-insensitively.
+func foldRanges(r rune) []rune {
+	rr := []rune{r, r}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		rr = append(rr, f, f)
+	}
+	return rr
+}
@@ -0,0 +1,306 @@
+package usrname_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"testing"
+	"unicode"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/github"
+	"github.com/jubobs/usrname/gitlab"
+	"github.com/jubobs/usrname/internal"
+	"github.com/jubobs/usrname/twitter"
+)
+
+func TestHTMLPattern(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		label    string
+		rules    *usrname.Rules
+		expected string
+	}{
+		{
+			"github",
+			github.New().Rules(),
+			`(?!-)(?![\s\S]*--)(?![\s\S]*-$)[\-0-9A-Za-z]{1,39}`,
+		}, {
+			"twitter",
+			twitter.New().Rules(),
+			`(?![\s\S]*(?:[Tt][Ww][Ii][Tt][Tt][Ee][Rr]))[0-9A-Z_a-z]{1,15}`,
+		}, {
+			"nowhitelist",
+			&usrname.Rules{MinLength: 2},
+			`[\s\S]{2,}`,
+		}, {
+			"escapes",
+			&usrname.Rules{
+				MinLength:         1,
+				MaxLength:         8,
+				Whitelist:         &unicode.RangeTable{R16: []unicode.Range16{{'a', 'e', 2}, {'é', 'é', 1}}},
+				IllegalSubstrings: []string{"/"},
+				IllegalPattern:    `^a+(b|c)?`,
+			},
+			`(?![\s\S]*\/)(?![\s\S]*(?:^(?:a)+(?:(?:[b-c]))?))[ace\u{e9}]{1,8}`,
+		},
+	}
+	const template = "HTMLPattern(), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			actual, err := c.rules.HTMLPattern()
+			if err != nil {
+				t.Fatalf("HTMLPattern(), unexpected error %v", err)
+			}
+			if actual != c.expected {
+				t.Errorf(template, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestHTMLPatternUnsupported(t *testing.T) {
+	defer leaktest.Check(t)()
+	rules := usrname.Rules{IllegalPattern: "(?m)^admin"}
+	if _, err := rules.HTMLPattern(); err == nil {
+		t.Errorf("HTMLPattern(), got no error, want one")
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	defer leaktest.Check(t)()
+	rules := usrname.Rules{
+		MinLength: 3,
+		MaxLength: 20,
+		Whitelist: &unicode.RangeTable{R16: []unicode.Range16{{'a', 'z', 1}}},
+	}
+	expected := map[string]interface{}{
+		"type":      "string",
+		"minLength": 3,
+		"maxLength": 20,
+		"pattern":   "^(?:[a-z]{3,20})$",
+	}
+	actual, err := rules.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema(), unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("JSONSchema(), got %v, want %v", actual, expected)
+	}
+}
+
+func TestRulesJSON(t *testing.T) {
+	defer leaktest.Check(t)()
+	expected := github.New().Rules()
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("json.Marshal, unexpected error %v", err)
+	}
+	var actual usrname.Rules
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("json.Unmarshal, unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(&actual, expected) {
+		t.Errorf("JSON round trip, got %#v, want %#v", &actual, expected)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("json.Unmarshal, unexpected error %v", err)
+	}
+	whitelist := []interface{}{
+		[]interface{}{45.0, 45.0},
+		[]interface{}{48.0, 57.0},
+		[]interface{}{65.0, 90.0},
+		[]interface{}{97.0, 122.0},
+	}
+	if !reflect.DeepEqual(raw["whitelist"], whitelist) {
+		t.Errorf("JSON whitelist, got %v, want %v", raw["whitelist"], whitelist)
+	}
+}
+
+func TestRulesCopy(t *testing.T) {
+	defer leaktest.Check(t)()
+	checker := gitlab.New()
+	checker.Rules().Reserved[0] = "foobar"
+	if vv := checker.Validate("foobar"); len(vv) != 0 {
+		t.Errorf("Validate after changing Rules(), got %v, want no violations", vv)
+	}
+}
+
+// unsupported lists the checkers whose rules, reserved names and all, lie
+// outside the subset that Regexp supports.
+var unsupported = map[string]bool{
+	".com":     true, // IDNA labels
+	".dev":     true,
+	".io":      true,
+	"Bluesky":  true,
+	"Codeberg": true,
+	"GitLab":   true,
+}
+
+func TestRegexp(t *testing.T) {
+	defer leaktest.Check(t)()
+	for _, name := range usrname.Checkers() {
+		checker, _ := usrname.CheckerFor(name)
+		t.Run(name, func(t *testing.T) {
+			re, err := checker.Rules().Regexp()
+			switch {
+			case unsupported[name] && isUnsupported(err):
+				t.Skipf("Regexp(), %v", err)
+			case unsupported[name]:
+				t.Fatalf("Regexp(), got error %v, want an *UnsupportedRulesError", err)
+			case err != nil:
+				t.Fatalf("Regexp(), unexpected error %v", err)
+			}
+			fuzzRegexp(t, re, checker.Rules(), func(username string) bool {
+				return len(checker.Validate(username)) == 0
+			})
+		})
+	}
+}
+
+func TestRegexpRules(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		label string
+		rules *usrname.Rules
+	}{
+		{
+			"unbounded",
+			&usrname.Rules{
+				MinLength:         2,
+				Whitelist:         &unicode.RangeTable{R16: []unicode.Range16{{'-', '.', 1}, {'a', 'c', 1}}},
+				IllegalPrefixes:   []string{"a-"},
+				IllegalSuffixes:   []string{".", "-"},
+				IllegalSubstrings: []string{"aba"},
+			},
+		}, {
+			"nowhitelist",
+			&usrname.Rules{
+				MinLength:      0,
+				MaxLength:      6,
+				IllegalPattern: `^[0-9]|b.c$`,
+			},
+		}, {
+			"emptysubstring",
+			&usrname.Rules{
+				MaxLength:         4,
+				IllegalSubstrings: []string{""},
+			},
+		}, {
+			"nonascii",
+			&usrname.Rules{
+				MinLength:      1,
+				MaxLength:      10,
+				Whitelist:      unicode.Latin,
+				IllegalPattern: `(?i)(é|ss)+k`,
+			},
+		}, {
+			"reserved",
+			&usrname.Rules{
+				MinLength: 1,
+				MaxLength: 5,
+				Whitelist: &unicode.RangeTable{R16: []unicode.Range16{{'A', 'C', 1}, {'a', 'c', 1}, {'s', 's', 1}}},
+				Reserved:  []string{"aba", "a", "ss", "abc"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			re, err := c.rules.Regexp()
+			if err != nil {
+				t.Fatalf("Regexp(), unexpected error %v", err)
+			}
+			fuzzRegexp(t, re, c.rules, func(username string) bool {
+				return len(validate(c.rules, username)) == 0
+			})
+		})
+	}
+}
+
+func TestRegexpUnsupported(t *testing.T) {
+	defer leaktest.Check(t)()
+	rules := usrname.Rules{IllegalPattern: `\badmin\b`}
+	if _, err := rules.Regexp(); !isUnsupported(err) {
+		t.Errorf("Regexp(), got error %v, want an *UnsupportedRulesError", err)
+	}
+}
+
+func isUnsupported(err error) bool {
+	_, ok := err.(*usrname.UnsupportedRulesError)
+	return ok
+}
+
+// fuzzRegexp checks that re, as compiled from rules, agrees with valid on
+// random strings made of runes that matter to rules.
+func fuzzRegexp(t *testing.T, re *regexp.Regexp, rules *usrname.Rules, valid func(string) bool) {
+	pieces := []string{"-", "_", ".", "^", "!", "é", "ß", "☃", "\n", "\xff", "K"}
+	pieces = append(pieces, rules.IllegalPrefixes...)
+	pieces = append(pieces, rules.IllegalSuffixes...)
+	pieces = append(pieces, rules.IllegalSubstrings...)
+	pieces = append(pieces, rules.Reserved...)
+	for _, lit := range []string{"twitter", "TwItTeR", "aba", "ssK", "Ék", "b.c", "0", "AbA", "sS"} {
+		pieces = append(pieces, lit)
+	}
+	if rules.Whitelist != nil {
+		for _, r := range rules.Whitelist.R16 {
+			for c := r.Lo; c <= r.Hi && c-r.Lo < 64; c += r.Stride {
+				pieces = append(pieces, string(rune(c)))
+			}
+		}
+	} else {
+		pieces = append(pieces, "a", "b", "c", "Z", "9")
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 5000; i++ {
+		var b bytes.Buffer
+		for n := rnd.Intn(rules.MaxLength + 4); b.Len() < n; {
+			b.WriteString(pieces[rnd.Intn(len(pieces))])
+		}
+		username := b.String()
+		if actual, expected := re.MatchString(username), valid(username); actual != expected {
+			t.Fatalf("MatchString(%q), got %t, want %t", username, actual, expected)
+		}
+	}
+}
+
+// validate is a reference implementation of the rules.
+func validate(r *usrname.Rules, username string) []usrname.Violation {
+	fs := []func(string) usrname.Violation{
+		internal.CheckLongerThan(r.MinLength),
+	}
+	if r.Whitelist != nil {
+		fs = append(fs, internal.CheckOnlyContains(r.Whitelist))
+	}
+	for _, p := range r.IllegalPrefixes {
+		fs = append(fs, internal.CheckIllegalPrefix(p))
+	}
+	for _, s := range r.IllegalSuffixes {
+		fs = append(fs, internal.CheckIllegalSuffix(s))
+	}
+	for _, s := range r.IllegalSubstrings {
+		fs = append(fs, internal.CheckIllegalSubstring(s))
+	}
+	if r.IllegalPattern != "" {
+		fs = append(fs, internal.CheckNotMatches(regexp.MustCompile(r.IllegalPattern)))
+	}
+	if len(r.Reserved) != 0 {
+		fs = append(fs, internal.CheckNotReserved(r.Reserved))
+	}
+	if r.IDNA {
+		fs = append(fs, internal.CheckIDNA())
+	}
+	if r.MaxLength != 0 {
+		fs = append(fs, internal.CheckShorterThan(r.MaxLength))
+	}
+	vv := []usrname.Violation{}
+	for _, f := range fs {
+		if v := f(username); v != nil {
+			vv = append(vv, v)
+		}
+	}
+	return vv
+}
@@ -0,0 +1,178 @@
+package soundcloud
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type soundcloud struct {
+	name            string
+	scheme          string
+	host            string
+	notFound        string // marks the soft-404 pages served for nonexistent users
+	found           string // marks the pages of existing profiles
+	illegalPrefixes []string
+	illegalSuffixes []string
+	whitelist       *unicode.RangeTable
+	minLength       int
+	maxLength       int
+	metadata        usrname.Metadata
+}
+
+var soundcloudImpl = soundcloud{
+	name:            "SoundCloud",
+	scheme:          "https",
+	host:            "soundcloud.com",
+	notFound:        `<title>Something went wrong on SoundCloud</title>`,
+	found:           `"kind":"user"`,
+	illegalPrefixes: []string{"-", "_"},
+	illegalSuffixes: []string{"-", "_"},
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 3,
+	maxLength: 25,
+	metadata: usrname.Metadata{
+		Category:  usrname.Creative,
+		Tags:      []string{"audio", "music"},
+		Homepage:  "https://soundcloud.com",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(soundcloudImpl.name, &soundcloudImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &soundcloudImpl
+}
+
+func (s *soundcloud) Name() string {
+	return s.name
+}
+
+func (s *soundcloud) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *soundcloud) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/" + username,
+	}
+	return u.String()
+}
+
+func (*soundcloud) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *soundcloud) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *soundcloud) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: append([]string(nil), v.illegalPrefixes...),
+		IllegalSuffixes: append([]string(nil), v.illegalSuffixes...),
+	}
+}
+
+// Profile URLs are stored in lowercase.
+func (*soundcloud) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://help.soundcloud.com/hc/en-us/articles/115003565628-Changing-your-profile-URL
+func (v *soundcloud) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefixes(v.illegalPrefixes),
+		internal.CheckIllegalSuffixes(v.illegalSuffixes),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *soundcloud) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			// The page shell is served whether the user exists or not; only
+			// the data embedded in it tells, and its markers come from
+			// hand-written fixtures, not from SoundCloud, so they only make
+			// the message more specific.
+			body, err := internal.ReadBody(res)
+			notFound := bytes.Contains(body, []byte(c.notFound))
+			found := bytes.Contains(body, []byte(c.found))
+			r.Status = usrname.UnknownStatus
+			switch {
+			case err != nil:
+				r.Message = "unexpected response body"
+			case notFound && !found:
+				r.Message = "error page, going by unverified markup"
+			case found && !notFound:
+				r.Message = "profile page, going by unverified markup"
+			default:
+				r.Message = "no profile data in page"
+			}
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+func (c *soundcloud) request(username string) *http.Request {
+	req, err := http.NewRequest("GET", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,202 @@
+package usrname
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Span locates the part of a username that a Violation is about, both in
+// runes and in grapheme clusters (i.e. user-perceived characters). Ranges are
+// half-open; an empty range marks a position between two characters.
+type Span struct {
+	Runes     [2]int
+	Graphemes [2]int
+}
+
+// Spans returns the spans of username that v points at, for every type of
+// violation that this package defines. Violations about length point at the
+// excess characters (TooLong) or at the end of the username (TooShort), and
+// Reserved and InvalidLabel point at the whole username. It fails if v is of another type,
+// or does not fit username, e.g. because v was reported for another
+// username.
+func Spans(username string, v Violation) ([]Span, error) {
+	n := utf8.RuneCountInString(username)
+	var rr [][2]int
+	switch v := v.(type) {
+	case *TooShort:
+		rr = append(rr, [2]int{n, n})
+	case *TooLong:
+		if v.Max < n {
+			rr = append(rr, [2]int{v.Max, n})
+		}
+	case *IllegalPrefix:
+		rr = append(rr, [2]int{0, utf8.RuneCountInString(v.Pattern)})
+	case *IllegalSuffix:
+		rr = append(rr, [2]int{n - utf8.RuneCountInString(v.Pattern), n})
+	case *IllegalSubstring:
+		start, end := -1, -1
+		if len(v.At) == 2 {
+			start, end = v.At[0], v.At[1]
+		} else if i := strings.Index(username, v.Pattern); i != -1 {
+			start, end = i, i+len(v.Pattern)
+		}
+		if start == -1 {
+			return nil, fmt.Errorf("usrname: %v does not fit %q", v, username)
+		}
+		r0, err := runeOffset(username, start)
+		if err != nil {
+			return nil, err
+		}
+		r1, err := runeOffset(username, end)
+		if err != nil {
+			return nil, err
+		}
+		rr = append(rr, [2]int{r0, r1})
+	case *Reserved, *InvalidLabel:
+		rr = append(rr, [2]int{0, n})
+	case *IllegalChars:
+		for _, i := range v.At {
+			r, err := runeOffset(username, i)
+			if err != nil {
+				return nil, err
+			}
+			rr = append(rr, [2]int{r, r + 1})
+		}
+	default:
+		return nil, fmt.Errorf("usrname: unsupported violation %T", v)
+	}
+	for _, r := range rr {
+		if r[0] < 0 || r[1] < r[0] || n < r[1] {
+			return nil, fmt.Errorf("usrname: %v does not fit %q", v, username)
+		}
+	}
+	bounds := graphemeBounds(username)
+	var ss []Span
+	for _, r := range rr {
+		s := Span{Runes: r}
+		s.Graphemes[0] = graphemeOffset(bounds, n, r[0], false)
+		s.Graphemes[1] = graphemeOffset(bounds, n, r[1], r[1] > r[0])
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
+// runeOffset converts a byte offset in s into a rune offset. It fails if i
+// is out of range or in the middle of a rune.
+func runeOffset(s string, i int) (int, error) {
+	if i < 0 || len(s) < i || i < len(s) && !utf8.RuneStart(s[i]) {
+		return 0, fmt.Errorf("usrname: byte offset %d out of range in %q", i, s)
+	}
+	return utf8.RuneCountInString(s[:i]), nil
+}
+
+// graphemeOffset converts a rune offset into a grapheme-cluster offset, given
+// the number of runes n and the rune offsets at which clusters start. Offsets
+// within a cluster are rounded down, or up if up is true.
+func graphemeOffset(bounds []int, n, r int, up bool) int {
+	if r >= n {
+		return len(bounds)
+	}
+	g := sort.SearchInts(bounds, r)
+	if g < len(bounds) && bounds[g] == r || up {
+		return g
+	}
+	return g - 1
+}
+
+// graphemeBounds returns the rune offsets at which the grapheme clusters of s
+// start. It implements a simplification of the extended grapheme cluster
+// boundaries of Unicode Standard Annex #29: prepended concatenation marks and
+// Indic conjuncts are not taken into account.
+func graphemeBounds(s string) []int {
+	var bounds []int
+	var prev rune
+	pict, ri := false, 0 // within an emoji sequence; count of regional indicators
+	i := 0
+	for _, r := range s {
+		if i == 0 || graphemeBreak(prev, r, pict, ri) {
+			bounds = append(bounds, i)
+			pict = false
+		}
+		switch {
+		case isPictographic(r):
+			pict = true
+		case !isExtend(r) && r != zwj:
+			pict = false
+		}
+		if isRegionalIndicator(r) {
+			ri++
+		} else {
+			ri = 0
+		}
+		prev = r
+		i++
+	}
+	return bounds
+}
+
+const zwj = '\u200d'
+
+func graphemeBreak(prev, r rune, pict bool, ri int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return false
+	case isControl(prev) || isControl(r):
+		return true
+	case isHangulL(prev) && (isHangulL(r) || isHangulV(r) || isHangulLV(r) || isHangulLVT(r)):
+		return false
+	case (isHangulLV(prev) || isHangulV(prev)) && (isHangulV(r) || isHangulT(r)):
+		return false
+	case (isHangulLVT(prev) || isHangulT(prev)) && isHangulT(r):
+		return false
+	case isExtend(r) || r == zwj || unicode.Is(unicode.Mc, r):
+		return false
+	case prev == zwj && pict && isPictographic(r):
+		return false
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return ri%2 == 0
+	}
+	return true
+}
+
+func isControl(r rune) bool {
+	return r != zwj && (unicode.Is(unicode.Cc, r) || unicode.Is(unicode.Zl, r) ||
+		unicode.Is(unicode.Zp, r) || unicode.Is(unicode.Cf, r) && r != '\u200c')
+}
+
+func isExtend(r rune) bool {
+	return unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) ||
+		r == '\u200c' || 0x1f3fb <= r && r <= 0x1f3ff || 0xe0020 <= r && r <= 0xe007f
+}
+
+func isPictographic(r rune) bool {
+	return 0x2600 <= r && r <= 0x27bf || 0x1f000 <= r && r <= 0x1faff &&
+		!isRegionalIndicator(r) && !(0x1f3fb <= r && r <= 0x1f3ff)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return 0x1f1e6 <= r && r <= 0x1f1ff
+}
+
+func isHangulL(r rune) bool {
+	return 0x1100 <= r && r <= 0x115f || 0xa960 <= r && r <= 0xa97c
+}
+
+func isHangulV(r rune) bool {
+	return 0x1160 <= r && r <= 0x11a7 || 0xd7b0 <= r && r <= 0xd7c6
+}
+
+func isHangulT(r rune) bool {
+	return 0x11a8 <= r && r <= 0x11ff || 0xd7cb <= r && r <= 0xd7fb
+}
+
+func isHangulLV(r rune) bool {
+	return 0xac00 <= r && r <= 0xd7a3 && (r-0xac00)%28 == 0
+}
+
+func isHangulLVT(r rune) bool {
+	return 0xac00 <= r && r <= 0xd7a3 && (r-0xac00)%28 != 0
+}
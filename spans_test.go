@@ -0,0 +1,173 @@
+package usrname_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/github"
+)
+
+func TestSpans(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		label     string
+		username  string
+		violation usrname.Violation
+		spans     []usrname.Span
+	}{
+		{
+			"illegalchars",
+			"exotic^chars",
+			&usrname.IllegalChars{At: []int{6}},
+			[]usrname.Span{{Runes: [2]int{6, 7}, Graphemes: [2]int{6, 7}}},
+		}, {
+			"aftercombiningmark",
+			"é^",
+			&usrname.IllegalChars{At: []int{3}},
+			[]usrname.Span{{Runes: [2]int{2, 3}, Graphemes: [2]int{1, 2}}},
+		}, {
+			"combiningmark",
+			"éx",
+			&usrname.IllegalChars{At: []int{1}},
+			[]usrname.Span{{Runes: [2]int{1, 2}, Graphemes: [2]int{0, 1}}},
+		}, {
+			"zwjsequence",
+			"\U0001F469‍\U0001F4BBx",
+			&usrname.IllegalChars{At: []int{0, 4, 7}},
+			[]usrname.Span{
+				{Runes: [2]int{0, 1}, Graphemes: [2]int{0, 1}},
+				{Runes: [2]int{1, 2}, Graphemes: [2]int{0, 1}},
+				{Runes: [2]int{2, 3}, Graphemes: [2]int{0, 1}},
+			},
+		}, {
+			"hanguljamo",
+			"가x",
+			&usrname.IllegalChars{At: []int{6}},
+			[]usrname.Span{{Runes: [2]int{2, 3}, Graphemes: [2]int{1, 2}}},
+		}, {
+			"tooshort",
+			"ab",
+			&usrname.TooShort{Min: 3, Actual: 2},
+			[]usrname.Span{{Runes: [2]int{2, 2}, Graphemes: [2]int{2, 2}}},
+		}, {
+			"toolong",
+			"héllo",
+			&usrname.TooLong{Max: 3, Actual: 5},
+			[]usrname.Span{{Runes: [2]int{3, 5}, Graphemes: [2]int{3, 5}}},
+		}, {
+			"illegalprefix",
+			"-a",
+			&usrname.IllegalPrefix{Pattern: "-"},
+			[]usrname.Span{{Runes: [2]int{0, 1}, Graphemes: [2]int{0, 1}}},
+		}, {
+			"illegalsuffix",
+			"ab--",
+			&usrname.IllegalSuffix{Pattern: "--"},
+			[]usrname.Span{{Runes: [2]int{2, 4}, Graphemes: [2]int{2, 4}}},
+		}, {
+			"illegalsubstringwithoutat",
+			"a--b",
+			&usrname.IllegalSubstring{Pattern: "--"},
+			[]usrname.Span{{Runes: [2]int{1, 3}, Graphemes: [2]int{1, 3}}},
+		}, {
+			"reserved",
+			"Admin",
+			&usrname.Reserved{Word: "admin"},
+			[]usrname.Span{{Runes: [2]int{0, 5}, Graphemes: [2]int{0, 5}}},
+		}, {
+			"invalidlabel",
+			"xn--bcher-kv",
+			&usrname.InvalidLabel{Reason: "punycode: truncated input"},
+			[]usrname.Span{{Runes: [2]int{0, 12}, Graphemes: [2]int{0, 12}}},
+		}, {
+			"illegalsubstringafterflag",
+			"\U0001F1EB\U0001F1F7admin",
+			&usrname.IllegalSubstring{Pattern: "admin", At: []int{8, 13}},
+			[]usrname.Span{{Runes: [2]int{2, 7}, Graphemes: [2]int{1, 6}}},
+		},
+	}
+	const template = "Spans(%q, %v), got %v, want %v"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			actual, err := usrname.Spans(c.username, c.violation)
+			if err != nil {
+				t.Fatalf("Spans(%q, %v), unexpected error %v", c.username, c.violation, err)
+			}
+			if !reflect.DeepEqual(actual, c.spans) {
+				t.Errorf(template, c.username, c.violation, actual, c.spans)
+			}
+		})
+	}
+}
+
+func TestSpansMismatch(t *testing.T) {
+	defer leaktest.Check(t)()
+	cases := []struct {
+		label     string
+		username  string
+		violation usrname.Violation
+	}{
+		{"charspastend", "ab", &usrname.IllegalChars{At: []int{7}}},
+		{"charsnegative", "ab", &usrname.IllegalChars{At: []int{-1}}},
+		{"charsmidrune", "é", &usrname.IllegalChars{At: []int{1}}},
+		{"substringpastend", "a--", &usrname.IllegalSubstring{Pattern: "--", At: []int{4, 6}}},
+		{"suffixtoolong", "a", &usrname.IllegalSuffix{Pattern: "--"}},
+		{"substringmissing", "ab", &usrname.IllegalSubstring{Pattern: "--"}},
+		{"unknowntype", "ab", "not a violation"},
+		{"nil", "ab", nil},
+	}
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if _, err := usrname.Spans(c.username, c.violation); err == nil {
+				t.Errorf("Spans(%q, %v), got no error, want one", c.username, c.violation)
+			}
+		})
+	}
+	if _, err := usrname.Carets("ab", []usrname.Violation{&usrname.IllegalChars{At: []int{7}}}); err == nil {
+		t.Errorf("Carets with mismatched violation, got no error, want one")
+	}
+}
+
+func TestCarets(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "-foo--b^r"
+	vv := github.New().Validate(username)
+	const expected = "-foo--b^r\n" +
+		"       ^   &IllegalChars{[7]}\n" +
+		"^          &IllegalPrefix{\"-\"}\n" +
+		"    ^^     &IllegalSubstring{\"--\"}\n"
+	if actual, _ := usrname.Carets(username, vv); actual != expected {
+		t.Errorf("Carets(%q, %v), got\n%s\nwant\n%s", username, vv, actual, expected)
+	}
+}
+
+func TestCaretsWide(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "日本x"
+	vv := []usrname.Violation{
+		&usrname.IllegalChars{At: []int{3}},
+		&usrname.TooShort{Min: 4, Actual: 3},
+	}
+	const expected = "日本x\n" +
+		"  ^^   &IllegalChars{[3]}\n" +
+		"     ^ &TooShort{Min: 4, Actual: 3}\n"
+	if actual, _ := usrname.Carets(username, vv); actual != expected {
+		t.Errorf("Carets(%q, %v), got\n%s\nwant\n%s", username, vv, actual, expected)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "-foo--b"
+	vv := []usrname.Violation{
+		&usrname.IllegalPrefix{Pattern: "-"},
+		&usrname.IllegalSubstring{Pattern: "--", At: []int{4, 6}},
+		&usrname.TooShort{Min: 8, Actual: 7},
+	}
+	const expected = "\x1b[1;4;31m-\x1b[0mfoo\x1b[1;4;31m--\x1b[0mb\x1b[7m \x1b[0m"
+	if actual, _ := usrname.Highlight(username, vv); actual != expected {
+		t.Errorf("Highlight(%q, %v), got %q, want %q", username, vv, actual, expected)
+	}
+}
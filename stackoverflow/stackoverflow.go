@@ -0,0 +1,202 @@
+package stackoverflow
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+// stackoverflow checks display names, which, unlike usernames, need not be
+// unique: every valid name is available, and the message of the result tells
+// whether someone already displays it.
+type stackoverflow struct {
+	name          string
+	scheme        string
+	host          string
+	apiHost       string
+	site          string // as known to the Stack Exchange API
+	illegalPrefix string
+	illegalSuffix string
+	whitelist     *unicode.RangeTable
+	minLength     int
+	maxLength     int
+	metadata      usrname.Metadata
+}
+
+var stackoverflowImpl = stackoverflow{
+	name:          "Stack Overflow",
+	scheme:        "https",
+	host:          "stackoverflow.com",
+	apiHost:       "api.stackexchange.com",
+	site:          "stackoverflow",
+	illegalPrefix: " ",
+	illegalSuffix: " ",
+	// printable characters, as unicode.IsPrint has them: letters, marks,
+	// numbers, punctuation, symbols and the ASCII space, but no controls or
+	// format characters (e.g. bidirectional overrides)
+	whitelist: internal.Union(
+		unicode.L,
+		unicode.M,
+		unicode.N,
+		unicode.P,
+		unicode.S,
+		&unicode.RangeTable{R16: []unicode.Range16{{' ', ' ', 1}}},
+	),
+	minLength: 3,
+	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Code,
+		Tags:      []string{"community", "q&a"},
+		Homepage:  "https://stackoverflow.com",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(stackoverflowImpl.name, &stackoverflowImpl); err != nil {
+		panic(err)
+	}
+	if err := usrname.RegisterAlias("so", stackoverflowImpl.name); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &stackoverflowImpl
+}
+
+func (s *stackoverflow) Name() string {
+	return s.name
+}
+
+func (s *stackoverflow) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *stackoverflow) Link(username string) string {
+	u := url.URL{
+		Scheme:   s.scheme,
+		Host:     s.host,
+		Path:     "/users",
+		RawQuery: url.Values{"search": {username}}.Encode(),
+	}
+	return u.String()
+}
+
+func (*stackoverflow) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *stackoverflow) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *stackoverflow) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: []string{v.illegalPrefix},
+		IllegalSuffixes: []string{v.illegalSuffix},
+	}
+}
+
+// Display names are searched case-insensitively.
+func (*stackoverflow) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://stackoverflow.com/help/user-profile
+func (v *stackoverflow) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefix(v.illegalPrefix),
+		internal.CheckIllegalSuffix(v.illegalSuffix),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *stackoverflow) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		if res.StatusCode != http.StatusOK {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			return
+		}
+
+		// The search matches display names that merely contain username,
+		// HTML-escaped, one page at a time.
+		var body struct {
+			Items []struct {
+				DisplayName string `json:"display_name"`
+			} `json:"items"`
+			HasMore bool `json:"has_more"`
+		}
+		if err := internal.DecodeJSON(res, &body); err != nil {
+			r.Status = usrname.UnknownStatus
+			r.Message = "unexpected response body"
+			return
+		}
+		// Display names need not be unique, so any valid one is available;
+		// whether someone already uses it is for information only.
+		r.Status = usrname.Available
+		for _, item := range body.Items {
+			if strings.EqualFold(html.UnescapeString(item.DisplayName), username) {
+				r.Message = "already in use, but display names need not be unique"
+				return
+			}
+		}
+		if body.HasMore {
+			r.Message = "too many similar display names to tell whether it is in use"
+		}
+		return
+	}
+}
+
+func (c *stackoverflow) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.apiHost,
+		Path:   "/2.3/users",
+		RawQuery: url.Values{
+			"inname":   {username},
+			"site":     {c.site},
+			"pagesize": {"100"},
+		}.Encode(),
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,200 @@
+package stackoverflow_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/mockclient"
+	"github.com/jubobs/usrname/stackoverflow"
+)
+
+var checker = stackoverflow.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Stack Overflow"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://stackoverflow.com/users?search=" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"ab",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    3,
+					Actual: 2,
+				},
+			},
+		}, {
+			"spaces",
+			"Jon Skeet",
+			noViolations,
+		}, {
+			"unicode",
+			"Zoë O'Brien",
+			noViolations,
+		}, {
+			"control",
+			"Jon\tSkeet\n",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3, 9},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"format", // bidirectional override and zero-width space
+			"Jon\u202eSkeet\u200b",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3, 11},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"spaceprefix",
+			" foobar",
+			[]usrname.Violation{
+				&usrname.IllegalPrefix{
+					Pattern: " ",
+				},
+			},
+		}, {
+			"spacesuffix",
+			"foobar ",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: " ",
+				},
+			},
+		}, {
+			"toolong",
+			"0123456789012345678901234567890",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    30,
+					Actual: 31,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+		message  string // if not empty
+	}{
+		{
+			label:    "invalid",
+			username: " obviously invalid ",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "match",
+			username: "jon skeet",
+			client:   mockclient.WithResponseFile("testdata/match.http"),
+			status:   usrname.Available,
+			message:  "already in use, but display names need not be unique",
+		}, {
+			label:    "escaped",
+			username: "Dan O'Reilly",
+			client:   mockclient.WithResponseFile("testdata/escaped.http"),
+			status:   usrname.Available,
+			message:  "already in use, but display names need not be unique",
+		}, {
+			label:    "substring",
+			username: "Skeet",
+			client:   mockclient.WithResponseFile("testdata/match.http"),
+			status:   usrname.Available,
+		}, {
+			label:    "nomatch",
+			username: "dummy",
+			client:   mockclient.WithResponseFile("testdata/nomatch.http"),
+			status:   usrname.Available,
+		}, {
+			label:    "hasmore",
+			username: "John",
+			client:   mockclient.WithResponseFile("testdata/hasmore.http"),
+			status:   usrname.Available,
+			message:  "too many similar display names to tell whether it is in use",
+		}, {
+			label:    "throttled",
+			username: "dummy",
+			client:   mockclient.WithResponseFile("testdata/throttled.http"),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+			if c.message != "" && res.Message != c.message {
+				t.Errorf("Check(%q), got message %q, want %q", c.username, res.Message, c.message)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -0,0 +1,165 @@
+package steam
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type steam struct {
+	name      string
+	scheme    string
+	host      string
+	notFound  string // marks the error pages served for unclaimed vanity URLs
+	found     string // marks the pages of existing profiles
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var steamImpl = steam{
+	name:     "Steam",
+	scheme:   "https",
+	host:     "steamcommunity.com",
+	notFound: "The specified profile could not be found.",
+	found:    "g_rgProfileData",
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '-', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 3,
+	maxLength: 32,
+	metadata: usrname.Metadata{
+		Category:  usrname.Gaming,
+		Homepage:  "https://steamcommunity.com",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(steamImpl.name, &steamImpl); err != nil {
+		panic(err)
+	}
+}
+
+// New returns the Checker for the vanity URLs of Steam Community profiles.
+func New() usrname.Checker {
+	return &steamImpl
+}
+
+func (s *steam) Name() string {
+	return s.name
+}
+
+func (s *steam) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *steam) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/id/" + username,
+	}
+	return u.String()
+}
+
+func (*steam) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *steam) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *steam) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Vanity URLs are case-insensitive.
+func (*steam) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://help.steampowered.com/en/faqs/view/2816-BE67-5B69-0FEC
+func (v *steam) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *steam) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		if res.StatusCode != http.StatusOK {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			return
+		}
+		// Unclaimed vanity URLs lead to an error page, served with status
+		// code 200. Its markers, and those of profiles, come from
+		// hand-written fixtures, not from Steam, so they only make the
+		// message more specific.
+		body, err := internal.ReadBody(res)
+		r.Status = usrname.UnknownStatus
+		switch {
+		case err != nil:
+			r.Message = "unexpected response body"
+		case bytes.Contains(body, []byte(c.notFound)):
+			r.Message = "error page, going by unverified markup"
+		case bytes.Contains(body, []byte(c.found)):
+			r.Message = "profile page, going by unverified markup"
+		default:
+			r.Message = "unexpected response body"
+		}
+		return
+	}
+}
+
+func (c *steam) request(username string) *http.Request {
+	req, err := http.NewRequest("GET", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,114 @@
+package usrname
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions bounds the number of names suggested for an unknown one.
+const maxSuggestions = 3
+
+// suggest returns the names, registered in r, that are close to name (or
+// have aliases that are), closest first. Only names of checkers are
+// suggested if checkersOnly is true.
+func (r *Registry) suggest(name string, checkersOnly bool) []string {
+	name = strings.ToLower(name)
+	// typos grow with the length of names
+	max := len([]rune(name)) / 3
+	if max < 1 {
+		max = 1
+	}
+	distances := make(map[string]int)
+	consider := func(candidate string, target string) {
+		if checkersOnly {
+			if _, ok := r.checkers[target]; !ok {
+				return
+			}
+		}
+		d := distance(name, strings.ToLower(candidate))
+		if d > max {
+			return
+		}
+		if prev, ok := distances[target]; !ok || d < prev {
+			distances[target] = d
+		}
+	}
+	for n := range r.validators {
+		consider(n, n)
+	}
+	for alias, n := range r.aliases {
+		consider(alias, n)
+	}
+	var list []string
+	for n := range distances {
+		list = append(list, n)
+	}
+	sort.Sort(byDistance{list, distances})
+	if len(list) > maxSuggestions {
+		list = list[:maxSuggestions]
+	}
+	return list
+}
+
+// byDistance sorts names by distance, then alphabetically.
+type byDistance struct {
+	names     []string
+	distances map[string]int
+}
+
+func (s byDistance) Len() int      { return len(s.names) }
+func (s byDistance) Swap(i, j int) { s.names[i], s.names[j] = s.names[j], s.names[i] }
+func (s byDistance) Less(i, j int) bool {
+	di, dj := s.distances[s.names[i]], s.distances[s.names[j]]
+	if di != dj {
+		return di < dj
+	}
+	return s.names[i] < s.names[j]
+}
+
+// didYouMean formats suggestions for inclusion in an error message.
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return " (did you mean " + strings.Join(suggestions, " or ") + "?)"
+}
+
+// distance returns the optimal string alignment distance between a and b,
+// in runes: the Levenshtein distance, with transpositions of adjacent runes
+// (a common typo) counting as one edit. Only the last three rows of the
+// matrix are kept.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1) // row i-2
+	prev := make([]int, len(rb)+1)  // row i-1
+	cur := make([]int, len(rb)+1)   // row i
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
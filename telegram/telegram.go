@@ -0,0 +1,172 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type telegram struct {
+	name           string
+	scheme         string
+	host           string
+	found          string // marks the pages of existing users, groups and channels
+	notFound       string // marks the pages of names that nobody holds
+	illegalPattern *regexp.Regexp
+	whitelist      *unicode.RangeTable
+	minLength      int
+	maxLength      int
+	metadata       usrname.Metadata
+}
+
+var telegramImpl = telegram{
+	name:           "Telegram",
+	scheme:         "https",
+	host:           "t.me",
+	found:          `<div class="tgme_page_title"`,
+	notFound:       `<i class="tgme_icon_user">`,
+	illegalPattern: regexp.MustCompile(`^[0-9_]`), // must start with a letter
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 5,
+	maxLength: 32,
+	metadata: usrname.Metadata{
+		Category:  usrname.Messaging,
+		Tags:      []string{"chat"},
+		Homepage:  "https://t.me",
+		RulesURL:  "https://core.telegram.org/method/account.checkUsername",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(telegramImpl.name, &telegramImpl); err != nil {
+		panic(err)
+	}
+	if err := usrname.RegisterAlias("tg", telegramImpl.name); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &telegramImpl
+}
+
+func (s *telegram) Name() string {
+	return s.name
+}
+
+func (s *telegram) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *telegram) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/" + username,
+	}
+	return u.String()
+}
+
+func (v *telegram) IllegalPattern() *regexp.Regexp {
+	return v.illegalPattern
+}
+
+func (v *telegram) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *telegram) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:      v.minLength,
+		MaxLength:      v.maxLength,
+		Whitelist:      v.whitelist,
+		IllegalPattern: v.illegalPattern.String(),
+	}
+}
+
+// Usernames are case-insensitive.
+func (*telegram) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://core.telegram.org/method/account.checkUsername
+func (v *telegram) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckNotMatches(v.illegalPattern),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *telegram) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		if res.StatusCode != http.StatusOK {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+			return
+		}
+		// Every name gets a page; those of existing accounts have a title,
+		// the others a placeholder icon. Both markers come from hand-written
+		// fixtures, not from Telegram, so they only make the message more
+		// specific.
+		body, err := internal.ReadBody(res)
+		found := err == nil && bytes.Contains(body, []byte(c.found))
+		notFound := err == nil && bytes.Contains(body, []byte(c.notFound))
+		r.Status = usrname.UnknownStatus
+		switch {
+		case found && !notFound:
+			r.Message = "account page, going by unverified markup"
+		case notFound && !found:
+			r.Message = "placeholder page, going by unverified markup"
+		default:
+			r.Message = "unexpected response body"
+		}
+		return
+	}
+}
+
+func (c *telegram) request(username string) *http.Request {
+	req, err := http.NewRequest("GET", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
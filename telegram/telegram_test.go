@@ -0,0 +1,184 @@
+package telegram_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/mockclient"
+	"github.com/jubobs/usrname/telegram"
+)
+
+var checker = telegram.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "Telegram"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://t.me/" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"abcd",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    5,
+					Actual: 4,
+				},
+			},
+		}, {
+			"underscores",
+			"Foo_bar_42",
+			noViolations,
+		}, {
+			"hyphen",
+			"foo-bar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"digitprefix",
+			"42foobar",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 1},
+				},
+			},
+		}, {
+			"underscoreprefix",
+			"_foobar",
+			[]usrname.Violation{
+				&usrname.IllegalSubstring{
+					Pattern: checker.IllegalPattern().String(),
+					At:      []int{0, 1},
+				},
+			},
+		}, {
+			"toolong",
+			"a12345678901234567890123456789012",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    32,
+					Actual: 33,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+		message  string // if not empty
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "account",
+			username: "durov",
+			client:   mockclient.WithResponseFile("testdata/account.http"),
+			status:   usrname.UnknownStatus,
+			message:  "account page, going by unverified markup",
+		}, {
+			label:    "noaccount",
+			username: "nosuchuser12345",
+			client:   mockclient.WithResponseFile("testdata/noaccount.http"),
+			status:   usrname.UnknownStatus,
+			message:  "placeholder page, going by unverified markup",
+		}, {
+			label:    "reshaped", // page without either marker
+			username: "durov",
+			client:   mockclient.WithResponseFile("testdata/reshaped.http"),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "blocked",
+			username: "dummy",
+			client:   mockclient.WithResponseFile("testdata/blocked.http"),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "other", // than 200
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusNotFound),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+			if c.message != "" && res.Message != c.message {
+				t.Errorf("Check(%q), got message %q, want %q", c.username, res.Message, c.message)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
@@ -0,0 +1,175 @@
+package tiktok
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type tiktok struct {
+	name          string
+	scheme        string
+	host          string
+	notFound      string // marks the pages of nonexistent accounts
+	illegalSuffix string
+	whitelist     *unicode.RangeTable
+	minLength     int
+	maxLength     int
+	metadata      usrname.Metadata
+}
+
+var tiktokImpl = tiktok{
+	name:          "TikTok",
+	scheme:        "https",
+	host:          "www.tiktok.com",
+	notFound:      `"statusCode":10221`,
+	illegalSuffix: ".",
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'.', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 2,
+	maxLength: 24,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"video"},
+		Homepage:  "https://www.tiktok.com",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(tiktokImpl.name, &tiktokImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &tiktokImpl
+}
+
+func (s *tiktok) Name() string {
+	return s.name
+}
+
+func (s *tiktok) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *tiktok) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/@" + username,
+	}
+	return u.String()
+}
+
+func (*tiktok) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *tiktok) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *tiktok) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalSuffixes: []string{v.illegalSuffix},
+	}
+}
+
+// Usernames are case-insensitive.
+func (*tiktok) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://support.tiktok.com/en/getting-started/setting-up-your-profile/changing-your-username
+func (v *tiktok) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalSuffix(v.illegalSuffix),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *tiktok) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			// Profile pages are served whether the account exists or not;
+			// only the data embedded in them tells, and its markers come from
+			// hand-written fixtures, not from TikTok, so they only make the
+			// message more specific.
+			body, err := internal.ReadBody(res)
+			r.Status = usrname.UnknownStatus
+			switch {
+			case err != nil:
+				r.Message = "unexpected response body"
+			case bytes.Contains(body, []byte(c.notFound)):
+				r.Message = "page of a nonexistent account, going by unverified data"
+			case bytes.Contains(bytes.ToLower(body), []byte(c.found(username))):
+				r.Message = "account page, going by unverified data"
+			default:
+				r.Message = "no account data in page"
+			}
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+// found returns the marker, in lowercase, of the page of username's account.
+func (c *tiktok) found(username string) string {
+	return fmt.Sprintf(`"uniqueid":"%s"`, c.Canonicalize(username))
+}
+
+func (c *tiktok) request(username string) *http.Request {
+	req, err := http.NewRequest("GET", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -0,0 +1,180 @@
+package tiktok_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/fortytw2/leaktest"
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/mockclient"
+	"github.com/jubobs/usrname/tiktok"
+)
+
+var checker = tiktok.New()
+
+func TestName(t *testing.T) {
+	defer leaktest.Check(t)()
+	const expected = "TikTok"
+	actual := checker.Name()
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestLink(t *testing.T) {
+	defer leaktest.Check(t)()
+	const username = "foobar"
+	const expected = "https://www.tiktok.com/@" + username
+	actual := checker.Link(username)
+	if actual != expected {
+		template := "got %q, want %q"
+		t.Errorf(template, actual, expected)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.Check(t)()
+	noViolations := []usrname.Violation{}
+	cases := []struct {
+		label      string
+		username   string
+		violations []usrname.Violation
+	}{
+		{
+			"tooshort",
+			"a",
+			[]usrname.Violation{
+				&usrname.TooShort{
+					Min:    2,
+					Actual: 1,
+				},
+			},
+		}, {
+			"specialchars",
+			"Foo.bar_baz",
+			noViolations,
+		}, {
+			"hyphen",
+			"foo-bar",
+			[]usrname.Violation{
+				&usrname.IllegalChars{
+					At:        []int{3},
+					Whitelist: checker.Whitelist(),
+				},
+			},
+		}, {
+			"periodsuffix",
+			"foobar.",
+			[]usrname.Violation{
+				&usrname.IllegalSuffix{
+					Pattern: ".",
+				},
+			},
+		}, {
+			"toolong",
+			"0123456789012345678901234",
+			[]usrname.Violation{
+				&usrname.TooLong{
+					Max:    24,
+					Actual: 25,
+				},
+			},
+		},
+	}
+	const template = "Validate(%q), got %s, want %s"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if vv := checker.Validate(c.username); !reflect.DeepEqual(vv, c.violations) {
+				t.Errorf(template, c.username, vv, c.violations)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	cases := []struct {
+		label    string
+		username string
+		client   usrname.Client
+		status   usrname.Status
+		message  string // if not empty
+	}{
+		{
+			label:    "invalid",
+			username: "_obviously_invalid!",
+			client:   nil,
+			status:   usrname.Invalid,
+		}, {
+			label:    "account",
+			username: "Taken",
+			client:   mockclient.WithResponseFile("testdata/account.http"),
+			status:   usrname.UnknownStatus,
+			message:  "account page, going by unverified data",
+		}, {
+			label:    "otheraccount",
+			username: "other",
+			client:   mockclient.WithResponseFile("testdata/account.http"),
+			status:   usrname.UnknownStatus,
+			message:  "no account data in page",
+		}, {
+			label:    "noaccount",
+			username: "dummy",
+			client:   mockclient.WithResponseFile("testdata/noaccount.http"),
+			status:   usrname.UnknownStatus,
+			message:  "page of a nonexistent account, going by unverified data",
+		}, {
+			label:    "captcha",
+			username: "dummy",
+			client:   mockclient.WithResponseFile("testdata/captcha.http"),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "notfound",
+			username: "dummy",
+			client:   mockclient.WithStatusCode(http.StatusNotFound),
+			status:   usrname.Available,
+		}, {
+			label:    "other", // than 200, 404
+			username: "dummy",
+			client:   mockclient.WithStatusCode(999),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "clienterror",
+			username: "dummy",
+			client:   mockclient.WithError(errors.New("Oh no!")),
+			status:   usrname.UnknownStatus,
+		}, {
+			label:    "timeouterror",
+			username: "dummy",
+			client:   mockclient.WithError(&timeoutError{}),
+			status:   usrname.UnknownStatus,
+		},
+	}
+
+	const template = "Check(%q), got %q, want %q"
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			res := checker.Check(c.client)(c.username)
+			actual := res.Status
+			expected := c.status
+			if actual != expected {
+				t.Errorf(template, c.username, actual, expected)
+			}
+			if c.message != "" && res.Message != c.message {
+				t.Errorf("Check(%q), got message %q, want %q", c.username, res.Message, c.message)
+			}
+		})
+	}
+}
+
+type timeoutError struct {
+	error
+}
+
+func (*timeoutError) Timeout() bool {
+	return true
+}
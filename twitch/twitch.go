@@ -0,0 +1,160 @@
+package twitch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type twitch struct {
+	name          string
+	scheme        string
+	host          string
+	apiHost       string
+	illegalPrefix string
+	whitelist     *unicode.RangeTable
+	minLength     int
+	maxLength     int
+	metadata      usrname.Metadata
+}
+
+var twitchImpl = twitch{
+	name:          "Twitch",
+	scheme:        "https",
+	host:          "www.twitch.tv",
+	apiHost:       "passport.twitch.tv",
+	illegalPrefix: "_",
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 4,
+	maxLength: 25,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"gaming", "streaming", "video"},
+		Homepage:  "https://www.twitch.tv",
+		Probeable: true,
+	},
+}
+
+func init() {
+	if err := usrname.Register(twitchImpl.name, &twitchImpl); err != nil {
+		panic(err)
+	}
+}
+
+func New() usrname.Checker {
+	return &twitchImpl
+}
+
+func (s *twitch) Name() string {
+	return s.name
+}
+
+func (s *twitch) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *twitch) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/" + username,
+	}
+	return u.String()
+}
+
+func (*twitch) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *twitch) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *twitch) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:       v.minLength,
+		MaxLength:       v.maxLength,
+		Whitelist:       v.whitelist,
+		IllegalPrefixes: []string{v.illegalPrefix},
+	}
+}
+
+// Usernames are case-insensitive.
+func (*twitch) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://help.twitch.tv/s/article/changing-your-username
+func (v *twitch) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckIllegalPrefix(v.illegalPrefix),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *twitch) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNoContent:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+// request asks the sign-up service, since channel pages are served (with
+// status code 200) whether the channel exists or not.
+func (c *twitch) request(username string) *http.Request {
+	u := url.URL{
+		Scheme: c.scheme,
+		Host:   c.apiHost,
+		Path:   "/usernames/" + username,
+	}
+	req, err := http.NewRequest("HEAD", u.String(), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}
@@ -5,10 +5,12 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/jubobs/usrname"
 	"github.com/jubobs/usrname/internal"
+	"github.com/jubobs/usrname/policy"
 )
 
 type twitter struct {
@@ -20,6 +22,7 @@ type twitter struct {
 	whitelist      *unicode.RangeTable
 	minLength      int
 	maxLength      int
+	metadata       usrname.Metadata
 }
 
 var twitterImpl = twitter{
@@ -27,7 +30,7 @@ var twitterImpl = twitter{
 	scheme:         "https",
 	host:           "twitter.com",
 	suspended:      "https://twitter.com/account/suspended",
-	illegalPattern: regexp.MustCompile("(?i)twitter"),
+	illegalPattern: policy.New("twitter").Pattern(),
 	whitelist: &unicode.RangeTable{
 		R16: []unicode.Range16{
 			{'0', '9', 1},
@@ -38,12 +41,22 @@ var twitterImpl = twitter{
 	},
 	minLength: 1,
 	maxLength: 15,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"microblogging"},
+		Homepage:  "https://twitter.com",
+		RulesURL:  "https://help.twitter.com/en/managing-your-account/twitter-username-rules",
+		Probeable: false,
+	},
 }
 
 func init() {
 	if err := usrname.Register(twitterImpl.name, &twitterImpl); err != nil {
 		panic(err)
 	}
+	if err := usrname.RegisterAlias("x", twitterImpl.name); err != nil {
+		panic(err)
+	}
 }
 
 func New() usrname.Checker {
@@ -54,6 +67,10 @@ func (t *twitter) Name() string {
 	return t.name
 }
 
+func (t *twitter) Metadata() *usrname.Metadata {
+	return t.metadata.Copy()
+}
+
 func (s *twitter) Link(username string) string {
 	u := url.URL{
 		Scheme: s.scheme,
@@ -71,6 +88,20 @@ func (v *twitter) Whitelist() *unicode.RangeTable {
 	return v.whitelist
 }
 
+func (v *twitter) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength:      v.minLength,
+		MaxLength:      v.maxLength,
+		Whitelist:      v.whitelist,
+		IllegalPattern: v.illegalPattern.String(),
+	}
+}
+
+// Usernames are case-insensitive.
+func (*twitter) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
 // See https://help.twitter.com/en/managing-your-account/twitter-username-rules
 func (v *twitter) Validate(username string) []usrname.Violation {
 	return internal.CheckAll(
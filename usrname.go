@@ -1,11 +1,7 @@
 package usrname
 
 import (
-	"errors"
-	"fmt"
 	"regexp"
-	"sort"
-	"sync"
 	"unicode"
 )
 
@@ -28,6 +24,7 @@ type Result struct {
 type Site interface {
 	Name() string
 	Link(username string) string
+	Metadata() *Metadata
 }
 
 type Validator interface {
@@ -35,6 +32,8 @@ type Validator interface {
 	Validate(username string) []Violation
 	IllegalPattern() *regexp.Regexp
 	Whitelist() *unicode.RangeTable
+	Rules() *Rules
+	Canonicalize(username string) string
 }
 
 type Checker interface {
@@ -42,42 +41,23 @@ type Checker interface {
 	Check(client Client) func(string) Result
 }
 
-var (
-	checkersMu sync.RWMutex
-	checkers   = make(map[string]Checker)
-)
-
-func Register(name string, checker Checker) error {
-	checkersMu.Lock()
-	defer checkersMu.Unlock()
-	if checker == nil {
-		return errors.New("usrname: Register checker is nil")
-	}
-	if _, dup := checkers[name]; dup {
-		return fmt.Errorf("usrname: Register called twice for checker %s", name)
-	}
-	checkers[name] = checker
-	return nil
+// Equivalent reports whether a and b designate the same account on v.
+func Equivalent(v Validator, a, b string) bool {
+	return v.Canonicalize(a) == v.Canonicalize(b)
 }
 
-func Checkers() []string {
-	checkersMu.RLock()
-	defer checkersMu.RUnlock()
+// Distinct returns usernames (e.g. candidates that a caller came up with)
+// without those that designate the same account on v as an earlier one.
+func Distinct(v Validator, usernames []string) []string {
+	seen := make(map[string]bool)
 	var list []string
-	for name := range checkers {
-		list = append(list, name)
+	for _, u := range usernames {
+		c := v.Canonicalize(u)
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		list = append(list, u)
 	}
-	sort.Strings(list)
 	return list
 }
-
-func CheckerFor(name string) (Checker, error) {
-	checkersMu.RLock()
-	defer checkersMu.RUnlock()
-	checker, exists := checkers[name]
-	if !exists {
-		err := fmt.Errorf("usrname: Checker not found for %s", name)
-		return nil, err
-	}
-	return checker, nil
-}
@@ -2,18 +2,46 @@ package usrname_test
 
 import (
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/fortytw2/leaktest"
 	"github.com/jubobs/usrname"
+	_ "github.com/jubobs/usrname/behance"
+	_ "github.com/jubobs/usrname/bluesky"
+	_ "github.com/jubobs/usrname/chesscom"
+	_ "github.com/jubobs/usrname/cratesio"
+	"github.com/jubobs/usrname/custom"
+	_ "github.com/jubobs/usrname/devto"
 	_ "github.com/jubobs/usrname/disqus"
+	_ "github.com/jubobs/usrname/dockerhub"
+	_ "github.com/jubobs/usrname/domain"
+	_ "github.com/jubobs/usrname/dribbble"
 	_ "github.com/jubobs/usrname/facebook"
+	_ "github.com/jubobs/usrname/fediverse"
+	_ "github.com/jubobs/usrname/gitea"
 	_ "github.com/jubobs/usrname/github"
+	_ "github.com/jubobs/usrname/gitlab"
+	_ "github.com/jubobs/usrname/hackernews"
 	_ "github.com/jubobs/usrname/instagram"
+	_ "github.com/jubobs/usrname/keybase"
+	_ "github.com/jubobs/usrname/lichess"
+	_ "github.com/jubobs/usrname/linktree"
+	_ "github.com/jubobs/usrname/matrix"
 	_ "github.com/jubobs/usrname/medium"
+	_ "github.com/jubobs/usrname/npm"
 	_ "github.com/jubobs/usrname/pinterest"
+	_ "github.com/jubobs/usrname/pypi"
 	_ "github.com/jubobs/usrname/reddit"
+	_ "github.com/jubobs/usrname/rubygems"
+	_ "github.com/jubobs/usrname/soundcloud"
+	_ "github.com/jubobs/usrname/stackoverflow"
+	_ "github.com/jubobs/usrname/steam"
+	_ "github.com/jubobs/usrname/telegram"
+	_ "github.com/jubobs/usrname/tiktok"
+	_ "github.com/jubobs/usrname/twitch"
 	_ "github.com/jubobs/usrname/twitter"
+	_ "github.com/jubobs/usrname/youtube"
 )
 
 const template = "Checkers(), got %q, want %q"
@@ -21,16 +49,117 @@ const template = "Checkers(), got %q, want %q"
 func TestCheckers(t *testing.T) {
 	defer leaktest.Check(t)()
 	expected := []string{
+		".com",
+		".dev",
+		".io",
+		"Behance",
+		"Bluesky",
+		"Chess.com",
+		"Codeberg",
+		"Dev.to",
 		"Disqus",
+		"Docker Hub",
+		"Dribbble",
+		"Fediverse",
 		"GitHub",
+		"GitLab",
+		"Hacker News",
 		"Instagram",
+		"Keybase",
+		"Lichess",
+		"Linktree",
+		"Matrix",
 		"Medium",
 		"Pinterest",
+		"PyPI",
+		"RubyGems",
+		"SoundCloud",
+		"Stack Overflow",
+		"Steam",
+		"Telegram",
+		"TikTok",
+		"Twitch",
 		"Twitter",
+		"YouTube",
+		"crates.io",
 		"facebook",
+		"npm",
+		"npm orgs",
 		"reddit",
 	}
 	if actual := usrname.Checkers(); !reflect.DeepEqual(actual, expected) {
 		t.Errorf(template, actual, expected)
 	}
 }
+
+func TestValidators(t *testing.T) {
+	defer leaktest.Check(t)()
+	v, err := custom.New(custom.Options{Name: "ACME"})
+	if err != nil {
+		t.Fatalf("custom.New, unexpected error %v", err)
+	}
+	if err := usrname.RegisterValidator("ACME", v); err != nil {
+		t.Fatalf("RegisterValidator, unexpected error %v", err)
+	}
+	if err := usrname.RegisterValidator("ACME", v); err == nil {
+		t.Errorf("RegisterValidator twice, got no error, want one")
+	}
+	if err := usrname.RegisterValidator("GitHub", v); err == nil {
+		t.Errorf("RegisterValidator(%q), got no error, want one", "GitHub")
+	}
+	if err := usrname.RegisterValidator("nil", nil); err == nil {
+		t.Errorf("RegisterValidator(nil), got no error, want one")
+	}
+
+	if actual, _ := usrname.ValidatorFor("ACME"); actual != v {
+		t.Errorf("ValidatorFor(%q), got %v, want %v", "ACME", actual, v)
+	}
+	if _, err := usrname.CheckerFor("ACME"); err == nil {
+		t.Errorf("CheckerFor(%q), got no error, want one", "ACME")
+	}
+	github, _ := usrname.CheckerFor("GitHub")
+	if actual, _ := usrname.ValidatorFor("GitHub"); actual != github {
+		t.Errorf("ValidatorFor(%q), got %v, want %v", "GitHub", actual, github)
+	}
+	if _, err := usrname.ValidatorFor("nonexistent"); err == nil {
+		t.Errorf("ValidatorFor(%q), got no error, want one", "nonexistent")
+	}
+
+	expected := append(usrname.Checkers(), "ACME")
+	sort.Strings(expected)
+	if actual := usrname.Validators(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Validators(), got %q, want %q", actual, expected)
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	defer leaktest.Check(t)()
+	const template = "Equivalent(%s, %q, %q), got %t, want %t"
+	for _, name := range usrname.Checkers() {
+		checker, _ := usrname.CheckerFor(name)
+		if !usrname.Equivalent(checker, "FooBar", "foobar") {
+			t.Errorf(template, name, "FooBar", "foobar", false, true)
+		}
+		if usrname.Equivalent(checker, "foobar", "foobaz") {
+			t.Errorf(template, name, "foobar", "foobaz", true, false)
+		}
+	}
+	facebook, _ := usrname.CheckerFor("facebook")
+	if !usrname.Equivalent(facebook, "Foo.Bar", "foobar") {
+		t.Errorf(template, "facebook", "Foo.Bar", "foobar", false, true)
+	}
+	github, _ := usrname.CheckerFor("GitHub")
+	if usrname.Equivalent(github, "foo-bar", "foobar") {
+		t.Errorf(template, "GitHub", "foo-bar", "foobar", true, false)
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	defer leaktest.Check(t)()
+	facebook, _ := usrname.CheckerFor("facebook")
+	usernames := []string{"Foo.Bar", "foobar", "foo.bar2", "FOOBAR2", "baz"}
+	expected := []string{"Foo.Bar", "foo.bar2", "baz"}
+	if actual := usrname.Distinct(facebook, usernames); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Distinct(facebook, %q), got %q, want %q", usernames, actual, expected)
+	}
+}
@@ -25,6 +25,8 @@ func (v *TooLong) String() string {
 	return fmt.Sprintf(templ, v.Max, v.Actual)
 }
 
+// At holds the byte offsets (not rune offsets) of the start and end of the
+// offending occurrence; Spans converts them into runes and graphemes.
 type IllegalSubstring struct {
 	At      []int
 	Pattern string
@@ -53,6 +55,8 @@ func (v *IllegalSuffix) String() string {
 	return fmt.Sprintf(templ, v.Pattern)
 }
 
+// At holds the byte offset (not the rune offset) of each illegal rune; Spans
+// converts them into runes and graphemes.
 type IllegalChars struct {
 	At        []int
 	Whitelist *unicode.RangeTable
@@ -62,3 +66,23 @@ func (v *IllegalChars) String() string {
 	const templ = "&IllegalChars{%v}"
 	return fmt.Sprintf(templ, v.At)
 }
+
+type Reserved struct {
+	Word string
+}
+
+func (v *Reserved) String() string {
+	const templ = "&Reserved{%q}"
+	return fmt.Sprintf(templ, v.Word)
+}
+
+// An InvalidLabel reports that a username is not a valid IDNA label (RFC
+// 5891), e.g. an A-label that does not decode into a valid U-label.
+type InvalidLabel struct {
+	Reason string
+}
+
+func (v *InvalidLabel) String() string {
+	const templ = "&InvalidLabel{%q}"
+	return fmt.Sprintf(templ, v.Reason)
+}
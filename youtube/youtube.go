@@ -0,0 +1,161 @@
+package youtube
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jubobs/usrname"
+	"github.com/jubobs/usrname/internal"
+)
+
+type youtube struct {
+	name      string
+	scheme    string
+	host      string
+	whitelist *unicode.RangeTable
+	minLength int
+	maxLength int
+	metadata  usrname.Metadata
+}
+
+var youtubeImpl = youtube{
+	name:   "YouTube",
+	scheme: "https",
+	host:   "www.youtube.com",
+	whitelist: &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{'-', '.', 1},
+			{'0', '9', 1},
+			{'A', 'Z', 1},
+			{'_', '_', 1},
+			{'a', 'z', 1},
+		},
+	},
+	minLength: 3,
+	maxLength: 30,
+	metadata: usrname.Metadata{
+		Category:  usrname.Social,
+		Tags:      []string{"video"},
+		Homepage:  "https://www.youtube.com",
+		RulesURL:  "https://support.google.com/youtube/answer/11585688",
+		Probeable: false,
+	},
+}
+
+func init() {
+	if err := usrname.Register(youtubeImpl.name, &youtubeImpl); err != nil {
+		panic(err)
+	}
+	if err := usrname.RegisterAlias("yt", youtubeImpl.name); err != nil {
+		panic(err)
+	}
+}
+
+// New returns the Checker for YouTube handles, which are given without their
+// leading "@".
+func New() usrname.Checker {
+	return &youtubeImpl
+}
+
+func (s *youtube) Name() string {
+	return s.name
+}
+
+func (s *youtube) Metadata() *usrname.Metadata {
+	return s.metadata.Copy()
+}
+
+func (s *youtube) Link(username string) string {
+	u := url.URL{
+		Scheme: s.scheme,
+		Host:   s.host,
+		Path:   "/@" + username,
+	}
+	return u.String()
+}
+
+func (*youtube) IllegalPattern() *regexp.Regexp {
+	return nil
+}
+
+func (v *youtube) Whitelist() *unicode.RangeTable {
+	return v.whitelist
+}
+
+func (v *youtube) Rules() *usrname.Rules {
+	return &usrname.Rules{
+		MinLength: v.minLength,
+		MaxLength: v.maxLength,
+		Whitelist: v.whitelist,
+	}
+}
+
+// Handles are case-insensitive.
+func (*youtube) Canonicalize(username string) string {
+	return strings.ToLower(username)
+}
+
+// See https://support.google.com/youtube/answer/11585688
+func (v *youtube) Validate(username string) []usrname.Violation {
+	return internal.CheckAll(
+		username,
+		internal.CheckLongerThan(v.minLength),
+		internal.CheckOnlyContains(v.whitelist),
+		internal.CheckShorterThan(v.maxLength),
+	)
+}
+
+func (c *youtube) Check(client usrname.Client) func(string) usrname.Result {
+	return func(username string) (r usrname.Result) {
+		r.Username = username
+		r.Checker = c
+
+		if vv := c.Validate(username); len(vv) != 0 {
+			r.Status = usrname.Invalid
+			const templ = "%q is invalid on %s"
+			r.Message = fmt.Sprintf(templ, username, c.Name())
+			return
+		}
+
+		req := c.request(username)
+		res, err := client.Do(req)
+		if err != nil {
+			r.Status = usrname.UnknownStatus
+			if internal.IsTimeout(err) {
+				r.Message = fmt.Sprintf("%s timed out", c.Name())
+			} else {
+				r.Message = "Something went wrong"
+			}
+			return
+		}
+		// In some regions, requests get redirected to a consent page, whose
+		// status code says nothing about the handle.
+		if res.Request != nil && res.Request.URL.Host != c.host {
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("redirected to %s", res.Request.URL.Host)
+			return
+		}
+		switch res.StatusCode {
+		case http.StatusOK:
+			r.Status = usrname.Unavailable
+		case http.StatusNotFound:
+			r.Status = usrname.Available
+		default:
+			r.Status = usrname.UnknownStatus
+			r.Message = fmt.Sprintf("unsupported status code %d", res.StatusCode)
+		}
+		return
+	}
+}
+
+func (c *youtube) request(username string) *http.Request {
+	req, err := http.NewRequest("HEAD", c.Link(username), nil)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return req
+}